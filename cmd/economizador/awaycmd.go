@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bytes"
+	"controle-arcondicionado/internal/app"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+func init() {
+	app.Register(app.Runner{
+		Name:     "away",
+		Synopsis: "activate or deactivate away mode on a running daemon",
+		Run:      runAwayCommand,
+	})
+}
+
+// runAwayCommand activates or deactivates away mode on a running daemon
+// through its local REST API.
+//
+//	economizador away [-until "2026-01-05 18:00"] [-precool]
+//	economizador away off
+func runAwayCommand(args []string) {
+	flags := flag.NewFlagSet("away", flag.ExitOnError)
+	apiAddr := flags.String("api", defaultAPIAddr(), "address of the daemon's REST API")
+	until := flags.String("until", "", "return date/time (format: 2006-01-02 15:04, local time)")
+	precool := flags.Bool("precool", false, "pre-cool rooms an hour before the return time")
+	flags.Parse(args)
+
+	if flags.NArg() == 1 && flags.Arg(0) == "off" {
+		req, err := http.NewRequest("DELETE", fmt.Sprintf("http://%s/away", *apiAddr), nil)
+		if err != nil {
+			logFatal("Failed to create request: %v", err)
+		}
+		doAwayRequest(req, *apiAddr)
+		logMsg("Away mode deactivated")
+		return
+	}
+	if flags.NArg() != 0 {
+		logFatal("Usage: economizador away [-until \"2006-01-02 15:04\"] [-precool] | away off")
+	}
+
+	payload := map[string]interface{}{"precool": *precool}
+	if *until != "" {
+		returnTime, err := time.ParseInLocation("2006-01-02 15:04", *until, time.Local)
+		if err != nil {
+			logFatal("Invalid -until value: %v", err)
+		}
+		payload["until"] = returnTime.Format(time.RFC3339)
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		logFatal("Failed to marshal request: %v", err)
+	}
+
+	req, err := http.NewRequest("POST", fmt.Sprintf("http://%s/away", *apiAddr), bytes.NewBuffer(body))
+	if err != nil {
+		logFatal("Failed to create request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	doAwayRequest(req, *apiAddr)
+
+	if *until != "" {
+		logMsg("Away mode activated until %s", *until)
+	} else {
+		logMsg("Away mode activated (no scheduled return)")
+	}
+}
+
+// doAwayRequest executes the request and fails loudly on errors.
+func doAwayRequest(req *http.Request, apiAddr string) {
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		logFatal("Failed to reach daemon API at %s: %v (is the daemon running?)", apiAddr, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		logFatal("API returned status %d: %s", resp.StatusCode, string(respBody))
+	}
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"controle-arcondicionado/internal/app"
+	"controle-arcondicionado/internal/thinq"
+	"flag"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+func init() {
+	app.Register(app.Runner{
+		Name:     "control",
+		Synopsis: "apply a declarative control plan to devices",
+		Run:      runControlCommand,
+	})
+}
+
+// controlPlan is the shape of the declarative control file: a list of
+// devices and the settings to apply to each one.
+type controlPlan struct {
+	Devices []controlStep `yaml:"devices"`
+}
+
+// controlStep holds the settings to apply to a single device. Omitted
+// fields are left untouched.
+type controlStep struct {
+	Name        string `yaml:"name"`        // device alias or ID
+	Power       *bool  `yaml:"power"`       // true = on, false = off
+	Mode        string `yaml:"mode"`        // job mode (COOL, FAN, AUTO, ...)
+	Temperature *int   `yaml:"temperature"` // target temperature in °C
+	Fan         string `yaml:"fan"`         // fan speed (LOW, MID, HIGH, AUTO)
+}
+
+// runControlCommand implements `economizador control apply -f plan.yaml`,
+// applying a declarative file of device settings in one shot.
+func runControlCommand(args []string) {
+	if len(args) < 1 || args[0] != "apply" {
+		logFatal("Usage: economizador control apply -f <plan.yaml>")
+	}
+
+	flags := flag.NewFlagSet("control apply", flag.ExitOnError)
+	planPath := flags.String("f", "", "path of the control plan YAML file")
+	flags.Parse(args[1:])
+
+	if *planPath == "" {
+		logFatal("Usage: economizador control apply -f <plan.yaml>")
+	}
+
+	data, err := os.ReadFile(*planPath)
+	if err != nil {
+		logFatal("Failed to read control plan: %v", err)
+	}
+	var plan controlPlan
+	if err := yaml.Unmarshal(data, &plan); err != nil {
+		logFatal("Failed to parse control plan %s: %v", *planPath, err)
+	}
+	if len(plan.Devices) == 0 {
+		logFatal("Control plan %s has no devices", *planPath)
+	}
+
+	boot, err := app.Load(logMsg)
+	if err != nil {
+		logFatal("%v", err)
+	}
+	client := boot.Client
+	devices, err := boot.Devices()
+	if err != nil {
+		logFatal("%v", err)
+	}
+
+	failures := 0
+	for _, step := range plan.Devices {
+		deviceID, err := thinq.ResolveDevice(devices, step.Name)
+		if err != nil {
+			logMsg("[%s] FAILED: %v", step.Name, err)
+			failures++
+			continue
+		}
+
+		if err := applyStep(client, deviceID, step); err != nil {
+			logMsg("[%s] FAILED: %v", step.Name, err)
+			failures++
+			continue
+		}
+		logMsg("[%s] OK", step.Name)
+	}
+
+	if failures > 0 {
+		logFatal("Control plan finished with %d failure(s)", failures)
+	}
+	logMsg("Control plan applied to %d device(s)", len(plan.Devices))
+}
+
+// applyStep sends the step's settings to the device, stopping at the first
+// failure. Power is applied first so the other settings land on a unit
+// that is awake.
+func applyStep(client *thinq.Client, deviceID string, step controlStep) error {
+	if step.Power != nil {
+		if err := client.SetPower(deviceID, *step.Power); err != nil {
+			return err
+		}
+	}
+	if step.Mode != "" {
+		if err := client.SetJobMode(deviceID, strings.ToUpper(step.Mode)); err != nil {
+			return err
+		}
+	}
+	if step.Temperature != nil {
+		if err := client.SetTemperature(deviceID, *step.Temperature); err != nil {
+			return err
+		}
+	}
+	if step.Fan != "" {
+		if err := client.SetWindStrength(deviceID, strings.ToUpper(step.Fan)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,250 @@
+package main
+
+import (
+	"controle-arcondicionado/internal/app"
+	"controle-arcondicionado/internal/rules"
+	"controle-arcondicionado/internal/statecache"
+	"controle-arcondicionado/internal/thinq"
+	"fmt"
+	"sync"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+func init() {
+	app.Register(app.Runner{
+		Name:     "dashboard",
+		Synopsis: "live terminal dashboard of device state",
+		Run:      runDashboard,
+	})
+}
+
+// dashboard is the terminal UI showing live device state, read from the
+// same state cache the daemon uses.
+type dashboard struct {
+	mu          sync.Mutex
+	app         *tview.Application
+	table       *tview.Table
+	status      *tview.TextView
+	client      *thinq.Client
+	devices     []thinq.Device
+	states      *statecache.Cache
+	corrections map[string]time.Time // last enforcement per device
+	order       []string             // deviceIDs in display order
+	resolver    *rules.Resolver
+	minTemp     int
+	paused      bool
+}
+
+// runDashboard shows a live TUI with device state and enforcement controls.
+func runDashboard(args []string) {
+	boot, err := app.Load(logMsg)
+	if err != nil {
+		logFatal("%v", err)
+	}
+	cfg := boot.Config
+	client := boot.Client
+
+	devices, err := boot.Devices()
+	if err != nil {
+		logFatal("%v", err)
+	}
+	if len(devices) == 0 {
+		logFatal("No devices found")
+	}
+
+	boot.SubscribeDevices(devices)
+
+	d := &dashboard{
+		app:         tview.NewApplication(),
+		table:       tview.NewTable().SetBorders(false).SetFixed(1, 0),
+		status:      tview.NewTextView().SetDynamicColors(true),
+		client:      client,
+		devices:     devices,
+		states:      statecache.New(devices, statecache.DefaultStaleAfter),
+		corrections: make(map[string]time.Time),
+		resolver:    rules.NewResolver(cfg, devices),
+		minTemp:     cfg.MinTemperature,
+	}
+	for _, device := range devices {
+		d.order = append(d.order, device.DeviceID)
+	}
+
+	handler := func(_ mqtt.Client, msg mqtt.Message) {
+		d.handleEvent(msg.Payload())
+	}
+
+	mqttClient, credentials, err := boot.ConnectMQTT(handler, nil)
+	if err != nil {
+		logFatal("%v", err)
+	}
+	defer boot.DisconnectMQTT(mqttClient, credentials)
+
+	d.table.SetSelectable(true, false)
+	d.table.Select(1, 0)
+
+	layout := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(d.table, 0, 1, true).
+		AddItem(d.status, 1, 0, false)
+
+	d.app.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Rune() {
+		case 'q':
+			d.app.Stop()
+			return nil
+		case 'p':
+			d.togglePause()
+			return nil
+		case '+', '=':
+			d.bumpSelected(1)
+			return nil
+		case '-':
+			d.bumpSelected(-1)
+			return nil
+		}
+		return event
+	})
+
+	d.redraw()
+	if err := d.app.SetRoot(layout, true).Run(); err != nil {
+		logFatal("Dashboard error: %v", err)
+	}
+}
+
+// handleEvent updates the table from an incoming MQTT payload and applies
+// minimum-temperature enforcement unless paused.
+func (d *dashboard) handleEvent(payload []byte) {
+	event, err := thinq.ParseEvent(payload)
+	if err != nil || event.PushType != "DEVICE_STATUS" {
+		return
+	}
+
+	d.states.Observe(event)
+
+	d.mu.Lock()
+	paused := d.paused
+	minTemp := d.resolver.PolicyFor(event.DeviceID).MinTemperature
+	needsCorrection := !paused && event.TargetTemperature != nil && int(*event.TargetTemperature) < minTemp
+	if needsCorrection {
+		d.corrections[event.DeviceID] = time.Now()
+	}
+	d.mu.Unlock()
+
+	if needsCorrection {
+		if err := d.client.SetTemperature(event.DeviceID, minTemp); err != nil {
+			logMsg("Failed to adjust temperature: %v", err)
+		}
+	}
+
+	d.app.QueueUpdateDraw(d.redraw)
+}
+
+// togglePause pauses or resumes enforcement.
+func (d *dashboard) togglePause() {
+	d.mu.Lock()
+	d.paused = !d.paused
+	d.mu.Unlock()
+	d.redraw()
+}
+
+// bumpSelected raises or lowers the selected device's target temperature.
+func (d *dashboard) bumpSelected(delta int) {
+	row, _ := d.table.GetSelection()
+	index := row - 1 // header row
+	if index < 0 || index >= len(d.order) {
+		return
+	}
+	deviceID := d.order[index]
+
+	minTemp := d.resolver.PolicyFor(deviceID).MinTemperature
+
+	state, _ := d.states.Get(deviceID)
+	target := minTemp
+	if state.TargetTemperature != nil {
+		target = int(*state.TargetTemperature)
+	}
+
+	newTarget := target + delta
+	if newTarget < minTemp {
+		newTarget = minTemp
+	}
+
+	go func() {
+		if err := d.client.SetTemperature(deviceID, newTarget); err != nil {
+			logMsg("Failed to set temperature of %s: %v", state.Alias, err)
+		}
+	}()
+}
+
+// redraw rebuilds the device table. Must run on the UI goroutine.
+func (d *dashboard) redraw() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	headers := []string{"Device", "Target", "Current", "Mode", "Power", "Last Correction"}
+	for col, header := range headers {
+		d.table.SetCell(0, col, tview.NewTableCell(header).
+			SetTextColor(tcell.ColorYellow).
+			SetSelectable(false))
+	}
+
+	for row, deviceID := range d.order {
+		state, _ := d.states.Get(deviceID)
+		name := state.Alias
+		if state.Stale {
+			name += " (stale)"
+		}
+		cells := []string{
+			name,
+			formatTemp(state.TargetTemperature),
+			formatTemp(state.CurrentTemperature),
+			formatValue(state.JobMode),
+			formatPower(state.PowerOn),
+			formatTime(d.corrections[deviceID]),
+		}
+		for col, text := range cells {
+			d.table.SetCell(row+1, col, tview.NewTableCell(text))
+		}
+	}
+
+	enforcement := "[green]active[-]"
+	if d.paused {
+		enforcement = "[red]paused[-]"
+	}
+	d.status.SetText(fmt.Sprintf(" Enforcement: %s | min: %d°C | [p] pause  [+/-] setpoint  [q] quit",
+		enforcement, d.minTemp))
+}
+
+func formatTemp(value *float64) string {
+	if value == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%.0f°C", *value)
+}
+
+func formatValue(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
+func formatPower(on *bool) string {
+	if on == nil {
+		return "-"
+	}
+	if *on {
+		return "ON"
+	}
+	return "OFF"
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Format("15:04:05")
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"encoding/json"
+	"hash/fnv"
+	"runtime/debug"
+)
+
+const (
+	poolWorkers    = 4
+	poolQueueDepth = 64
+)
+
+// poolJob is one MQTT message waiting to be processed.
+type poolJob struct {
+	topic   string
+	payload []byte
+}
+
+// handlerPool fans MQTT messages out to a bounded set of workers, so a
+// slow control call never blocks the paho client. Messages for the same
+// device always land on the same worker, preserving per-device ordering,
+// and every message runs with panic recovery so a malformed payload
+// cannot kill the daemon.
+type handlerPool struct {
+	queues []chan poolJob
+	handle func(topic string, payload []byte)
+}
+
+// newHandlerPool starts the workers around the given message handler.
+func newHandlerPool(handle func(topic string, payload []byte)) *handlerPool {
+	p := &handlerPool{
+		queues: make([]chan poolJob, poolWorkers),
+		handle: handle,
+	}
+	for i := range p.queues {
+		p.queues[i] = make(chan poolJob, poolQueueDepth)
+		go p.worker(p.queues[i])
+	}
+	return p
+}
+
+// Dispatch routes a message to the worker owning its device. When that
+// worker's queue is full the message is dropped with a log entry rather
+// than blocking the MQTT client.
+func (p *handlerPool) Dispatch(topic string, payload []byte) {
+	var envelope struct {
+		DeviceID string `json:"deviceId"`
+	}
+	_ = json.Unmarshal(payload, &envelope)
+
+	hash := fnv.New32a()
+	hash.Write([]byte(envelope.DeviceID))
+	queue := p.queues[hash.Sum32()%uint32(len(p.queues))]
+
+	select {
+	case queue <- poolJob{topic: topic, payload: payload}:
+	default:
+		logMsg("Handler queue full, dropping a message for device %q", envelope.DeviceID)
+	}
+}
+
+// Depth returns the number of messages waiting across all workers.
+func (p *handlerPool) Depth() int {
+	depth := 0
+	for _, queue := range p.queues {
+		depth += len(queue)
+	}
+	return depth
+}
+
+func (p *handlerPool) worker(queue <-chan poolJob) {
+	for job := range queue {
+		p.run(job)
+	}
+}
+
+// run executes one message with panic recovery.
+func (p *handlerPool) run(job poolJob) {
+	defer func() {
+		if r := recover(); r != nil {
+			logMsg("Panic while handling a message: %v\n%s", r, debug.Stack())
+		}
+	}()
+	p.handle(job.topic, job.payload)
+}
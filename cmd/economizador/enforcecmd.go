@@ -0,0 +1,117 @@
+package main
+
+import (
+	"controle-arcondicionado/internal/app"
+	"controle-arcondicionado/internal/rules"
+	"controle-arcondicionado/internal/store"
+	"controle-arcondicionado/internal/thinq"
+	"flag"
+	"os"
+)
+
+func init() {
+	app.Register(app.Runner{
+		Name:     "enforce-once",
+		Synopsis: "poll every device, apply pending policy corrections and exit",
+		Run:      runEnforceOnce,
+	})
+}
+
+// countingController wraps a Controller and counts the control commands it
+// issues, so the one-shot run can print a summary.
+type countingController struct {
+	inner rules.Controller
+	calls int
+}
+
+func (c *countingController) SetTemperature(deviceID string, temperature int) error {
+	c.calls++
+	return c.inner.SetTemperature(deviceID, temperature)
+}
+
+func (c *countingController) SetPower(deviceID string, on bool) error {
+	c.calls++
+	return c.inner.SetPower(deviceID, on)
+}
+
+func (c *countingController) SetJobMode(deviceID string, mode string) error {
+	c.calls++
+	return c.inner.SetJobMode(deviceID, mode)
+}
+
+func (c *countingController) SetTwoSetTemperature(deviceID string, coolTarget, heatTarget int) error {
+	c.calls++
+	return c.inner.SetTwoSetTemperature(deviceID, coolTarget, heatTarget)
+}
+
+func (c *countingController) SetWindStrength(deviceID string, strength string) error {
+	c.calls++
+	return c.inner.SetWindStrength(deviceID, strength)
+}
+
+func (c *countingController) SetPowerSave(deviceID string, on bool) error {
+	c.calls++
+	return c.inner.SetPowerSave(deviceID, on)
+}
+
+func (c *countingController) SetTargetHumidity(deviceID string, humidity int) error {
+	c.calls++
+	return c.inner.SetTargetHumidity(deviceID, humidity)
+}
+
+// runEnforceOnce polls the current state of every device over REST, runs
+// each state through the rules engine once and exits: no MQTT session, no
+// daemon. Made for a cron entry on constrained machines.
+func runEnforceOnce(args []string) {
+	flags := flag.NewFlagSet("enforce-once", flag.ExitOnError)
+	dryRun := flags.Bool("n", false, "log corrections without sending them")
+	flags.Parse(args)
+
+	boot, err := app.Load(logMsg)
+	if err != nil {
+		logFatal("%v", err)
+	}
+	cfg := boot.Config
+
+	devices, err := boot.Devices()
+	if err != nil {
+		logFatal("%v", err)
+	}
+
+	var inner rules.Controller = boot.Client
+	if *dryRun {
+		inner = dryRunClient{}
+	}
+	client := &countingController{inner: inner}
+
+	resolver := rules.NewResolver(cfg, devices)
+	engine := rules.NewEngine(client, resolver, devices, newNotifier(cfg), logMsg)
+
+	// Respect runtime mode overrides and scene relaxations left by the
+	// daemon, but leave the snapshot alone: this run is transient
+	if cfg.StateFile != "off" {
+		if saved, err := store.Open(cfg.StateFile).Load(); err == nil && saved != nil {
+			engine.Restore(saved)
+		}
+	}
+
+	logMsg("Polling %d device(s)...", len(devices))
+	polled := 0
+	failed := 0
+	for _, device := range devices {
+		state, err := boot.Client.GetDeviceState(device.DeviceID)
+		if err != nil {
+			logMsg("[%s] Failed to poll state: %v", device.Alias, err)
+			failed++
+			continue
+		}
+		engine.HandleEvent(thinq.EventFromState(device.DeviceID, state))
+		polled++
+	}
+
+	logMsg("Enforcement pass complete: %d device(s) checked, %d control call(s) issued, %d poll failure(s)",
+		polled, client.calls, failed)
+	if failed > 0 && polled == 0 {
+		os.Exit(1)
+	}
+}
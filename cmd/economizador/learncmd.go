@@ -0,0 +1,170 @@
+package main
+
+import (
+	"controle-arcondicionado/internal/app"
+	"controle-arcondicionado/internal/config"
+	"controle-arcondicionado/internal/history"
+	"controle-arcondicionado/internal/learn"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+)
+
+func init() {
+	app.Register(app.Runner{
+		Name:     "learn",
+		Synopsis: "review and accept auto-learned quiet-hour profiles",
+		Run:      runLearnCommand,
+	})
+}
+
+// runLearnCommand mines the history for quiet-hour suggestions and manages
+// the accepted profiles: suggest (default), list, accept <device> and
+// drop <device>. The daemon only enforces accepted profiles.
+func runLearnCommand(args []string) {
+	flags := flag.NewFlagSet("learn", flag.ExitOnError)
+	file := flags.String("f", learn.DefaultProfilesFile, "accepted profiles file")
+	flags.Parse(args)
+	rest := flags.Args()
+
+	action := "suggest"
+	if len(rest) > 0 {
+		action = rest[0]
+	}
+
+	switch action {
+	case "suggest":
+		suggestions := analyzeHistory()
+		if len(suggestions) == 0 {
+			fmt.Println("No suggestions yet: not enough history, or no device has a clear quiet period.")
+			return
+		}
+		accepted := loadAcceptedWindows(*file)
+		fmt.Println("Suggested quiet-hour profiles:")
+		for _, suggestion := range suggestions {
+			marker := ""
+			if window, ok := accepted[strings.ToLower(suggestion.Alias)]; ok && window == suggestion.Window {
+				marker = "  (accepted)"
+			}
+			fmt.Printf("  %-20s off %s  based on %d days of history%s\n",
+				suggestion.Alias, suggestion.Window, suggestion.Days, marker)
+		}
+		fmt.Println()
+		fmt.Println("Accept one with: economizador learn accept <device>")
+
+	case "list":
+		profiles, err := learn.LoadProfiles(*file)
+		if err != nil {
+			logFatal("%v", err)
+		}
+		if len(profiles) == 0 {
+			fmt.Println("No accepted profiles.")
+			return
+		}
+		for _, profile := range profiles {
+			fmt.Printf("  %-20s off %s\n", profile.Device, profile.Off)
+		}
+
+	case "accept":
+		if len(rest) < 2 {
+			logFatal("Usage: economizador learn accept <device>")
+		}
+		name := strings.Join(rest[1:], " ")
+
+		var match *learn.Suggestion
+		for _, suggestion := range analyzeHistory() {
+			if strings.EqualFold(suggestion.Alias, name) {
+				s := suggestion
+				match = &s
+				break
+			}
+		}
+		if match == nil {
+			logFatal("No suggestion for %q (run `economizador learn` to see them)", name)
+		}
+
+		profiles, err := learn.LoadProfiles(*file)
+		if err != nil {
+			logFatal("%v", err)
+		}
+		profiles = removeProfile(profiles, match.Alias)
+		profiles = append(profiles, learn.Profile{Device: match.Alias, Off: match.Window})
+		if err := learn.SaveProfiles(*file, profiles); err != nil {
+			logFatal("%v", err)
+		}
+		fmt.Printf("Accepted: %s stays off %s. Restart the daemon to apply.\n", match.Alias, match.Window)
+
+	case "drop":
+		if len(rest) < 2 {
+			logFatal("Usage: economizador learn drop <device>")
+		}
+		name := strings.Join(rest[1:], " ")
+
+		profiles, err := learn.LoadProfiles(*file)
+		if err != nil {
+			logFatal("%v", err)
+		}
+		remaining := removeProfile(profiles, name)
+		if len(remaining) == len(profiles) {
+			logFatal("No accepted profile for %q", name)
+		}
+		if err := learn.SaveProfiles(*file, remaining); err != nil {
+			logFatal("%v", err)
+		}
+		fmt.Printf("Dropped the profile of %s. Restart the daemon to apply.\n", name)
+
+	default:
+		logFatal("Unknown learn action %q (expected suggest, list, accept or drop)", action)
+	}
+}
+
+// analyzeHistory reads the full history file and returns the suggested
+// quiet-hour profiles.
+func analyzeHistory() []learn.Suggestion {
+	cfg, err := config.Load()
+	if err != nil {
+		logFatal("Failed to load configuration: %v", err)
+	}
+	if cfg.HistoryFile == "off" {
+		logFatal("History is disabled (HISTORY_FILE=off)")
+	}
+
+	store, err := history.Open(cfg.HistoryFile)
+	if err != nil {
+		logFatal("Failed to open history: %v", err)
+	}
+	defer store.Close()
+
+	entries, err := store.ReadSince(time.Time{})
+	if err != nil {
+		logFatal("Failed to read history: %v", err)
+	}
+	return learn.Analyze(entries)
+}
+
+// loadAcceptedWindows returns the accepted windows keyed by lowercased
+// device alias, ignoring a missing or broken profiles file.
+func loadAcceptedWindows(path string) map[string]learn.Window {
+	accepted := make(map[string]learn.Window)
+	profiles, err := learn.LoadProfiles(path)
+	if err != nil {
+		return accepted
+	}
+	for _, profile := range profiles {
+		accepted[strings.ToLower(profile.Device)] = profile.Off
+	}
+	return accepted
+}
+
+// removeProfile drops the profile of the named device, matching the alias
+// case-insensitively.
+func removeProfile(profiles []learn.Profile, name string) []learn.Profile {
+	remaining := profiles[:0:0]
+	for _, profile := range profiles {
+		if !strings.EqualFold(profile.Device, name) {
+			remaining = append(remaining, profile)
+		}
+	}
+	return remaining
+}
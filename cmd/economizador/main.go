@@ -1,15 +1,32 @@
 package main
 
 import (
+	"context"
+	"controle-arcondicionado/internal/anomaly"
+	"controle-arcondicionado/internal/api"
+	"controle-arcondicionado/internal/app"
+	"controle-arcondicionado/internal/bridge"
 	"controle-arcondicionado/internal/config"
+	"controle-arcondicionado/internal/grpcapi"
+	"controle-arcondicionado/internal/history"
+	"controle-arcondicionado/internal/learn"
+	"controle-arcondicionado/internal/metrics"
+	"controle-arcondicionado/internal/notify"
+	"controle-arcondicionado/internal/presence"
+	"controle-arcondicionado/internal/rules"
+	"controle-arcondicionado/internal/statecache"
+	"controle-arcondicionado/internal/store"
+	"controle-arcondicionado/internal/systemd"
 	"controle-arcondicionado/internal/thinq"
-	"crypto/tls"
-	"encoding/json"
+	"controle-arcondicionado/internal/tracing"
+	"controle-arcondicionado/internal/winsvc"
+	"flag"
 	"fmt"
 	"io"
 	"log"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"syscall"
 	"time"
 
@@ -21,7 +38,21 @@ var logger *log.Logger
 func initLogger() (*os.File, error) {
 	logFile, err := os.OpenFile("economizador.log", os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open log file: %w", err)
+		// The working directory may not be writable (e.g. a Windows
+		// service started from Program Files): fall back to the
+		// per-user config directory
+		dir, dirErr := os.UserConfigDir()
+		if dirErr != nil {
+			return nil, fmt.Errorf("failed to open log file: %w", err)
+		}
+		dir = filepath.Join(dir, "economizador")
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, fmt.Errorf("failed to create log directory: %w", err)
+		}
+		logFile, err = os.OpenFile(filepath.Join(dir, "economizador.log"), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open log file: %w", err)
+		}
 	}
 
 	multiWriter := io.MultiWriter(os.Stdout, logFile)
@@ -38,6 +69,30 @@ func logFatal(format string, args ...interface{}) {
 	logger.Fatalf(format, args...)
 }
 
+// newPresenceSource builds the configured presence source, preferring
+// Home Assistant when both are configured.
+func newPresenceSource(cfg *config.PresenceConfig) (presence.Source, error) {
+	if ha := cfg.HomeAssistant; ha != nil {
+		if ha.URL == "" || ha.Token == "" || len(ha.Entities) == 0 {
+			return nil, fmt.Errorf("home_assistant presence requires url, token and entities")
+		}
+		return presence.NewHomeAssistantSource(ha.URL, ha.Token, ha.Entities), nil
+	}
+	if len(cfg.PingHosts) > 0 {
+		return presence.NewPingSource(cfg.PingHosts), nil
+	}
+	return nil, fmt.Errorf("presence requires either home_assistant or ping_hosts")
+}
+
+// newNotifier returns the configured notifier: a webhook when one is set,
+// otherwise the daemon log.
+func newNotifier(cfg *config.Config) notify.Notifier {
+	if cfg.NotifyWebhook != "" {
+		return notify.NewWebhookNotifier(cfg.NotifyWebhook)
+	}
+	return &notify.LogNotifier{Logf: logMsg}
+}
+
 func main() {
 	// Initialize logger
 	logFile, err := initLogger()
@@ -46,10 +101,66 @@ func main() {
 	}
 	defer logFile.Close()
 
-	// Load configuration
-	cfg, err := config.Load()
+	// Dispatch subcommands from the runner registry; the default
+	// (no subcommand) runs the daemon
+	if len(os.Args) > 1 {
+		if runner, ok := app.Lookup(os.Args[1]); ok {
+			runner.Run(os.Args[2:])
+			return
+		}
+	}
+
+	runDaemon(os.Args[1:])
+}
+
+func runDaemon(args []string) {
+	if winsvc.IsService() {
+		// Under the Windows service control manager stop requests
+		// arrive through the SCM instead of signals
+		if err := winsvc.Run("economizador", func(stop <-chan struct{}) {
+			daemon(args, stop)
+		}); err != nil {
+			logFatal("Failed to run as a Windows service: %v", err)
+		}
+		return
+	}
+
+	stop := make(chan struct{})
+	go func() {
+		sigChan := make(chan os.Signal, 1)
+		signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+		<-sigChan
+		close(stop)
+	}()
+	daemon(args, stop)
+}
+
+func daemon(args []string, stop <-chan struct{}) {
+	flags := flag.NewFlagSet("economizador", flag.ExitOnError)
+	recordPath := flags.String("record", "", "record raw MQTT payloads to the given NDJSON file")
+	flags.Parse(args)
+
+	// Load configuration and ThinQ client through the shared bootstrap
+	boot, err := app.Load(logMsg)
 	if err != nil {
-		logFatal("Failed to load configuration: %v", err)
+		logFatal("%v", err)
+	}
+	cfg := boot.Config
+
+	// Enable OpenTelemetry export when OTEL_EXPORTER_OTLP_ENDPOINT is set
+	shutdownTracing, tracingEnabled, err := tracing.Setup(context.Background(), "economizador")
+	if err != nil {
+		logFatal("Failed to set up tracing: %v", err)
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			logMsg("Warning: Failed to flush traces: %v", err)
+		}
+	}()
+	if tracingEnabled {
+		logMsg("OpenTelemetry tracing enabled")
 	}
 
 	logMsg("=== LG ThinQ Energy Saver ===")
@@ -57,179 +168,408 @@ func main() {
 	logMsg("Country Code: %s", cfg.CountryCode)
 	logMsg("Client ID: %s", cfg.ClientID)
 
-	// Create ThinQ client
-	client := thinq.NewClient(cfg.ThinQPAT, cfg.CountryCode, cfg.ClientID)
-
-	// Get MQTT broker
-	logMsg("Getting MQTT broker information...")
-	mqttServer, err := client.GetMQTTRoute()
-	if err != nil {
-		logFatal("Failed to get MQTT route: %v", err)
-	}
-	logMsg("MQTT Server: %s", mqttServer)
+	client := boot.Client
 
 	// Get device list first
 	logMsg("Fetching devices...")
-	devices, err := client.GetDeviceList()
+	devices, err := boot.Devices()
 	if err != nil {
-		logFatal("Failed to get device list: %v", err)
+		logFatal("%v", err)
 	}
 	logMsg("Found %d device(s)", len(devices))
 
-	// Subscribe to events for each device
-	logMsg("Subscribing to device events and push notifications...")
-	for i, device := range devices {
-		logMsg("[%d/%d] Subscribing to: %s", i+1, len(devices), device.Alias)
-
-		// Subscribe to events
-		if err := client.SubscribeToDeviceEvents(device.DeviceID); err != nil {
-			logMsg("Warning: Failed to subscribe to events for %s: %v", device.Alias, err)
+	// Resolve effective policies (defaults + zones + device overrides)
+	resolver := rules.NewResolver(cfg, devices)
+	for _, device := range devices {
+		policy := resolver.PolicyFor(device.DeviceID)
+		zone := resolver.ZoneOf(device.DeviceID)
+		if zone == "" {
+			zone = "-"
 		}
+		logMsg("Policy for %s (zone: %s): min %d°C", device.Alias, zone, policy.MinTemperature)
+	}
+
+	// Last known state of every device, fed from events and polling; the
+	// REST API, metrics and rules all read from this single cache
+	states := statecache.New(devices, statecache.DefaultStaleAfter)
+	stopPolling := startStatePolling(states, client, devices)
+	defer stopPolling()
+
+	// Subscribe to events for each device; optionally fail fast when too
+	// few devices could be subscribed
+	subscribed := boot.SubscribeDevices(devices)
+	if cfg.MinSubscribed > 0 && subscribed < cfg.MinSubscribed {
+		logFatal("Only %d device(s) subscribed successfully (MIN_SUBSCRIBED=%d), aborting", subscribed, cfg.MinSubscribed)
+	}
 
-		// Subscribe to push notifications
-		if err := client.SubscribeToPushNotifications(device.DeviceID); err != nil {
-			logMsg("Warning: Failed to subscribe to push for %s: %v", device.Alias, err)
+	// Optionally record raw payloads for offline replay
+	var recorder *Recorder
+	if *recordPath != "" {
+		recorder, err = NewRecorder(*recordPath)
+		if err != nil {
+			logFatal("Failed to open record file: %v", err)
 		}
+		defer recorder.Close()
+		logMsg("Recording MQTT payloads to %s", *recordPath)
 	}
-	logMsg("Subscription complete!")
 
-	// Get MQTT credentials
-	logMsg("Obtaining MQTT credentials...")
-	credentials, err := client.GetMQTTCredentials()
-	if err != nil {
-		logFatal("Failed to get MQTT credentials: %v", err)
+	// Connect with the enforcement message handler; control calls go
+	// through a retry queue so corrections survive LG API outages
+	notifier := newNotifier(cfg)
+	controls := rules.NewControlQueue(client, logMsg)
+	engine := rules.NewEngine(controls, resolver, devices, notifier, logMsg)
+	engine.SetScenes(cfg.Scenes)
+	engine.SetStateCache(states)
+	engine.SetEnergySavingSupport(detectEnergySaving(client, resolver, devices))
+
+	// Escalate when the same device keeps getting corrected
+	if cfg.EscalateAfter > 0 {
+		engine.SetEscalation(cfg.EscalateAfter, cfg.EscalateLock)
+		logMsg("Escalation enabled (threshold: %d corrections/hour, lock: %v)", cfg.EscalateAfter, cfg.EscalateLock)
 	}
-	logMsg("Received certificate and %d subscription topic(s)", len(credentials.Subscriptions))
 
-	// Setup TLS configuration
-	tlsConfig, err := createTLSConfig(credentials)
-	if err != nil {
-		logFatal("Failed to create TLS config: %v", err)
-	}
-
-	// Setup MQTT options with message handler
-	messageHandler := createMessageHandler(client, devices, cfg.MinTemperature)
-	opts := mqtt.NewClientOptions()
-	opts.AddBroker(fmt.Sprintf("ssl://%s", mqttServer))
-	opts.SetClientID(cfg.ClientID)
-	opts.SetTLSConfig(tlsConfig)
-	opts.SetKeepAlive(60 * time.Second)
-	opts.SetPingTimeout(10 * time.Second)
-	opts.SetDefaultPublishHandler(messageHandler)
-	opts.SetConnectionLostHandler(func(client mqtt.Client, err error) {
-		logMsg("Connection lost: %v", err)
-	})
-	opts.SetOnConnectHandler(func(client mqtt.Client) {
-		logMsg("Connected to MQTT broker!")
-
-		// Subscribe to all topics
-		for _, topic := range credentials.Subscriptions {
-			logMsg("Subscribing to: %s", topic)
-			if token := client.Subscribe(topic, 1, nil); token.Wait() && token.Error() != nil {
-				logMsg("Failed to subscribe to %s: %v", topic, token.Error())
+	// Enforce the quiet-hour profiles accepted via `economizador learn`
+	if profiles, err := learn.LoadProfiles(learn.DefaultProfilesFile); err != nil {
+		logMsg("Warning: Failed to load learned profiles: %v", err)
+	} else if len(profiles) > 0 {
+		windows := make(map[string]learn.Window)
+		for _, profile := range profiles {
+			deviceID, err := thinq.ResolveDevice(devices, profile.Device)
+			if err != nil {
+				logMsg("Learned profile: %v", err)
+				continue
 			}
+			windows[deviceID] = profile.Off
+			logMsg("Learned profile: %s stays off %s", profile.Device, profile.Off)
 		}
-		logMsg("Energy Saver Active! Minimum allowed: %d°C (press Ctrl+C to stop)...", cfg.MinTemperature)
-	})
+		engine.SetLearnedProfiles(windows)
+	}
+
+	// Republish events to a local broker when one is configured
+	var localBridge *bridge.Bridge
+	if cfg.LocalMQTTURL != "" {
+		localBridge, err = bridge.New(bridge.Options{
+			URL:      cfg.LocalMQTTURL,
+			Username: cfg.LocalMQTTUsername,
+			Password: cfg.LocalMQTTPassword,
+			Prefix:   cfg.LocalMQTTPrefix,
+			CAFile:   cfg.LocalMQTTCAFile,
+		}, devices, logMsg)
+		if err != nil {
+			logFatal("Failed to start local MQTT bridge: %v", err)
+		}
+		defer localBridge.Close()
 
-	// Create and start MQTT client
-	mqttClient := mqtt.NewClient(opts)
-	if token := mqttClient.Connect(); token.Wait() && token.Error() != nil {
-		logFatal("Failed to connect to MQTT broker: %v", token.Error())
+		if err := localBridge.ListenCommands(client); err != nil {
+			logFatal("Failed to listen for local commands: %v", err)
+		}
+	}
+
+	// Alert when a running unit fails to actually cool the room
+	var detector *anomaly.Detector
+	if cfg.AnomalyMinutes > 0 {
+		window := time.Duration(cfg.AnomalyMinutes) * time.Minute
+		detector = anomaly.NewDetector(devices, notifier, window, cfg.AnomalyMinDrop, logMsg)
+		logMsg("Anomaly detection enabled (window: %s, expected drop: %.1f°C)", window, cfg.AnomalyMinDrop)
 	}
 
-	// Wait for interrupt signal
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
-	<-sigChan
+	// Climate gauges for Prometheus, served by the local API under /metrics
+	var collector *metrics.Collector
+	if cfg.APIListen != "off" {
+		collector = metrics.NewCollector(states, resolver.ZoneOf)
+		collector.SetSchemaMismatches(client.SchemaMismatches)
+	}
 
-	// Unsubscribe from all topics
-	logMsg("Unsubscribing from topics...")
-	for _, topic := range credentials.Subscriptions {
-		if token := mqttClient.Unsubscribe(topic); token.Wait() && token.Error() != nil {
-			logMsg("Failed to unsubscribe from %s: %v", topic, token.Error())
+	// Restore the enforcement state and the device state cache from the
+	// last run and keep snapshotting both
+	if cfg.StateFile != "off" {
+		stateStore := store.Open(cfg.StateFile)
+		saved, err := stateStore.Load()
+		if err != nil {
+			logMsg("Warning: Failed to load enforcement state: %v", err)
+		} else if saved != nil {
+			engine.Restore(saved)
 		}
+		cachePath := filepath.Join(filepath.Dir(cfg.StateFile), "statecache.json")
+		if err := states.Load(cachePath); err != nil {
+			logMsg("Warning: Failed to load state cache: %v", err)
+		}
+		stopStateSaver := startStateSaver(engine, stateStore, states, cachePath)
+		defer stopStateSaver()
 	}
 
-	logMsg("Disconnecting from MQTT broker...")
-	mqttClient.Disconnect(250)
-	logMsg("Energy Saver stopped. Goodbye!")
-}
+	// gRPC API for programmatic integrations (event streaming, control)
+	var grpcServer *grpcapi.Server
+	if cfg.GRPCListen != "" {
+		grpcServer = grpcapi.NewServer(engine, client, devices, logMsg)
+		stopGRPC, err := grpcServer.Start(cfg.GRPCListen)
+		if err != nil {
+			logFatal("Failed to start gRPC server: %v", err)
+		}
+		defer stopGRPC()
+	}
 
-// createMessageHandler creates a message handler that adjusts temperature
-func createMessageHandler(client *thinq.Client, devices []thinq.Device, minTemperature int) mqtt.MessageHandler {
-	// Create device alias map for friendly names
-	deviceAliases := make(map[string]string)
-	for _, device := range devices {
-		deviceAliases[device.DeviceID] = device.Alias
+	// Process messages on a worker pool: per-device ordering, panic
+	// isolation and no blocking of the paho callback
+	pool := newHandlerPool(createEventProcessor(engine, recorder, localBridge, states, grpcServer, detector))
+	if collector != nil {
+		collector.SetQueueDepth(pool.Depth)
+	}
+	messageHandler := func(_ mqtt.Client, msg mqtt.Message) {
+		pool.Dispatch(msg.Topic(), msg.Payload())
 	}
 
-	return func(_ mqtt.Client, msg mqtt.Message) {
-		var event map[string]interface{}
-		if err := json.Unmarshal(msg.Payload(), &event); err != nil {
-			return
+	// Record activity for reports and schedule their delivery
+	daemonStart := time.Now()
+	if cfg.HistoryFile != "off" {
+		store, err := history.Open(cfg.HistoryFile)
+		if err != nil {
+			logFatal("Failed to open history: %v", err)
 		}
+		defer store.Close()
+		engine.SetHistory(store)
 
-		// Check if it's a device status event
-		pushType, ok := event["pushType"].(string)
-		if !ok || pushType != "DEVICE_STATUS" {
-			return
+		stopReports := startReportScheduler(cfg, store, notifier, daemonStart)
+		defer stopReports()
+
+		if cfg.HistoryDays > 0 {
+			stopCompaction := startHistoryCompaction(store, time.Duration(cfg.HistoryDays)*24*time.Hour)
+			defer stopCompaction()
 		}
+	}
 
-		deviceID, ok := event["deviceId"].(string)
-		if !ok {
-			return
+	// Presence-aware enforcement: apply the away policy when nobody is home
+	if cfg.Presence != nil {
+		source, err := newPresenceSource(cfg.Presence)
+		if err != nil {
+			logFatal("Failed to configure presence: %v", err)
 		}
+		awayPolicy := rules.AwayPolicy{
+			MinTemperature: cfg.Presence.Away.MinTemperature,
+			PowerOff:       cfg.Presence.Away.PowerOff,
+		}
+		watcher := presence.NewWatcher(source, time.Duration(cfg.Presence.PollSeconds)*time.Second, func(home bool) {
+			if home {
+				logMsg("Presence: someone is home again")
+				engine.SetAway(false, awayPolicy)
+			} else {
+				logMsg("Presence: nobody home, applying away policy")
+				engine.SetAway(true, awayPolicy)
+			}
+		}, logMsg)
+		stopPresence := watcher.Start()
+		defer stopPresence()
+		logMsg("Presence watcher started (every %ds)", cfg.Presence.PollSeconds)
+	}
 
-		// Get device alias
-		alias := deviceAliases[deviceID]
-		if alias == "" {
-			alias = deviceID
+	// Start the local REST API (mode switching, device inspection)
+	if cfg.APIListen != "off" {
+		apiServer := api.NewServer(engine, devices, logMsg)
+		apiServer.SetMetrics(collector)
+		apiServer.SetStateCache(states)
+		if err := apiServer.Start(cfg.APIListen); err != nil {
+			logFatal("Failed to start API server: %v", err)
+		}
+	}
+	mqttClient, credentials, err := boot.ConnectMQTT(messageHandler, func() {
+		// Tell systemd we are up once the broker connection is established
+		if err := systemd.NotifyReady(); err != nil {
+			logMsg("Warning: Failed to notify systemd: %v", err)
 		}
+		logMsg("Energy Saver Active! Minimum allowed: %d°C (press Ctrl+C to stop)...", cfg.MinTemperature)
+	})
+	if err != nil {
+		logFatal("%v", err)
+	}
 
-		// Check report for target temperature
-		report, ok := event["report"].(map[string]interface{})
-		if !ok {
-			return
+	// Optionally send control commands over the broker connection instead
+	// of the REST API
+	if cfg.ControlPath == "mqtt" {
+		publish := func(topic string, payload []byte) error {
+			token := mqttClient.Publish(topic, 1, false, payload)
+			token.Wait()
+			return token.Error()
 		}
+		if client.EnableMQTTControl(publish, credentials.Publications) {
+			logMsg("Control commands will be sent over MQTT (topic: %s)", credentials.Publications[0])
+		} else {
+			logMsg("Warning: CONTROL_PATH=mqtt but no publication topic was granted; keeping REST")
+		}
+	}
 
-		temperature, ok := report["temperature"].(map[string]interface{})
-		if !ok {
-			return
+	// Feed the systemd watchdog while the broker connection is healthy
+	stopWatchdog := startWatchdog(mqttClient)
+	defer stopWatchdog()
+
+	// Wait for an interrupt signal or a service stop request
+	<-stop
+
+	if err := systemd.NotifyStopping(); err != nil {
+		logMsg("Warning: Failed to notify systemd: %v", err)
+	}
+	boot.DisconnectMQTT(mqttClient, credentials)
+	logMsg("Energy Saver stopped. Goodbye!")
+}
+
+// createEventProcessor creates the per-message processor that feeds events
+// through the rules engine, optionally recording the raw payloads first,
+// forwarding them to the local MQTT bridge and updating the shared state
+// cache. It runs on the handler pool's workers.
+func createEventProcessor(engine *rules.Engine, recorder *Recorder, localBridge *bridge.Bridge, states *statecache.Cache, grpcServer *grpcapi.Server, detector *anomaly.Detector) func(topic string, payload []byte) {
+	return func(topic string, payload []byte) {
+		if recorder != nil {
+			if err := recorder.Record(topic, payload); err != nil {
+				logMsg("Failed to record payload: %v", err)
+			}
+		}
+		if localBridge != nil {
+			localBridge.Forward(payload)
 		}
 
-		// Check if target temperature is set and below minimum
-		targetTemp, hasTarget := temperature["targetTemperature"].(float64)
-		if !hasTarget {
+		event, err := thinq.ParseEvent(payload)
+		if err != nil {
 			return
 		}
+		states.Observe(event)
+		if grpcServer != nil {
+			grpcServer.Broadcast(event)
+		}
+		if detector != nil {
+			detector.Observe(event)
+		}
+		engine.HandleEvent(event)
+	}
+}
 
-		// Only adjust if temperature is below minimum
-		if int(targetTemp) >= minTemperature {
-			return
+// detectEnergySaving fetches the profile of every device whose policy asks
+// for energy saving and returns the ones exposing the native powerSave
+// switch. Models without it silently keep setpoint clamping alone.
+func detectEnergySaving(client *thinq.Client, resolver *rules.Resolver, devices []thinq.Device) map[string]bool {
+	supported := make(map[string]bool)
+	for _, device := range devices {
+		if !resolver.PolicyFor(device.DeviceID).EnergySaving {
+			continue
+		}
+		profile, err := client.GetDeviceProfile(device.DeviceID)
+		if err != nil {
+			logMsg("Failed to fetch profile of %s: %v", device.Alias, err)
+			continue
+		}
+		if thinq.SupportsPowerSave(profile) {
+			supported[device.DeviceID] = true
+			logMsg("Energy saving: %s exposes the native powerSave switch", device.Alias)
+		} else {
+			logMsg("Energy saving: %s has no powerSave support, keeping setpoint rules only", device.Alias)
 		}
+	}
+	return supported
+}
 
-		// Adjust temperature to minimum
-		logMsg("[%s] Temperature at %.0f°C (below minimum), adjusting to %d°C...",
-			alias, targetTemp, minTemperature)
+// startStateSaver snapshots the engine's enforcement state and the device
+// state cache every minute and once more on shutdown. The returned
+// function stops the saver.
+func startStateSaver(engine *rules.Engine, stateStore *store.Store, states *statecache.Cache, cachePath string) func() {
+	ticker := time.NewTicker(time.Minute)
+	done := make(chan struct{})
 
-		if err := client.SetTemperature(deviceID, minTemperature); err != nil {
-			logMsg("Failed to adjust temperature: %v", err)
-		} else {
-			logMsg("Temperature adjusted successfully!")
+	save := func() {
+		if err := stateStore.Save(engine.Snapshot()); err != nil {
+			logMsg("Failed to save enforcement state: %v", err)
+		}
+		if err := states.Save(cachePath); err != nil {
+			logMsg("Failed to save state cache: %v", err)
 		}
 	}
+
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				save()
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		ticker.Stop()
+		close(done)
+		save()
+	}
 }
 
-// createTLSConfig creates TLS configuration from credentials
-func createTLSConfig(credentials *thinq.MQTTCredentials) (*tls.Config, error) {
-	// Load client certificate
-	cert, err := tls.X509KeyPair([]byte(credentials.Certificate), []byte(credentials.PrivateKey))
-	if err != nil {
-		return nil, fmt.Errorf("failed to load client certificate: %w", err)
+// startHistoryCompaction rolls old raw history entries up into hourly
+// aggregates once a day (and once at startup). The returned function stops
+// the job.
+func startHistoryCompaction(store *history.Store, retention time.Duration) func() {
+	ticker := time.NewTicker(24 * time.Hour)
+	done := make(chan struct{})
+
+	compact := func() {
+		compacted, err := store.Compact(retention)
+		if err != nil {
+			logMsg("History compaction failed: %v", err)
+		} else if compacted > 0 {
+			logMsg("History compaction rolled up %d old entries", compacted)
+		}
 	}
 
-	return &tls.Config{
-		Certificates: []tls.Certificate{cert},
-	}, nil
+	go func() {
+		compact()
+		for {
+			select {
+			case <-ticker.C:
+				compact()
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		ticker.Stop()
+		close(done)
+	}
+}
+
+// startStatePolling refreshes the state cache from the state API every few
+// minutes, so cached values stay current (and do not go stale) for devices
+// that rarely emit events. The returned function stops the polling.
+func startStatePolling(states *statecache.Cache, client *thinq.Client, devices []thinq.Device) func() {
+	ticker := time.NewTicker(5 * time.Minute)
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				for _, device := range devices {
+					state, err := client.GetDeviceState(device.DeviceID)
+					if err != nil {
+						logMsg("Failed to poll state of %s: %v", device.Alias, err)
+						continue
+					}
+					states.Observe(thinq.EventFromState(device.DeviceID, state))
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		ticker.Stop()
+		close(done)
+	}
+}
+
+// processEvent runs a single raw MQTT payload through the rules engine.
+// It is shared between the live message handler and the replay command.
+func processEvent(engine *rules.Engine, payload []byte) {
+	event, err := thinq.ParseEvent(payload)
+	if err != nil {
+		return
+	}
+	engine.HandleEvent(event)
 }
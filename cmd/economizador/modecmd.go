@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bytes"
+	"controle-arcondicionado/internal/app"
+	"controle-arcondicionado/internal/rules"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+)
+
+func init() {
+	app.Register(app.Runner{
+		Name:     "mode",
+		Synopsis: "switch a device's enforcement mode on a running daemon",
+		Run:      runModeCommand,
+	})
+}
+
+// runModeCommand switches a device's enforcement mode on a running daemon
+// through its local REST API.
+func runModeCommand(args []string) {
+	flags := flag.NewFlagSet("mode", flag.ExitOnError)
+	apiAddr := flags.String("api", defaultAPIAddr(), "address of the daemon's REST API")
+	flags.Parse(args)
+
+	if flags.NArg() != 2 {
+		logFatal("Usage: economizador mode [-api addr] <device> <enforce|notify|log>")
+	}
+	device := flags.Arg(0)
+	mode, err := rules.ParseMode(flags.Arg(1))
+	if err != nil {
+		logFatal("%v", err)
+	}
+
+	body, err := json.Marshal(map[string]string{"mode": string(mode)})
+	if err != nil {
+		logFatal("Failed to marshal request: %v", err)
+	}
+
+	url := fmt.Sprintf("http://%s/devices/%s/mode", *apiAddr, device)
+	req, err := http.NewRequest("PUT", url, bytes.NewBuffer(body))
+	if err != nil {
+		logFatal("Failed to create request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		logFatal("Failed to reach daemon API at %s: %v (is the daemon running?)", *apiAddr, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		logFatal("API returned status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	logMsg("Mode of %s set to %s", device, mode)
+}
+
+// defaultAPIAddr matches the daemon's default API listen address.
+func defaultAPIAddr() string {
+	if addr := os.Getenv("API_LISTEN"); addr != "" && addr != "off" {
+		return addr
+	}
+	return "127.0.0.1:8765"
+}
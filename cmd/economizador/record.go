@@ -0,0 +1,65 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// RecordedMessage is one captured MQTT payload, stored as a single NDJSON line.
+type RecordedMessage struct {
+	Timestamp time.Time       `json:"timestamp"`
+	Topic     string          `json:"topic"`
+	Payload   json.RawMessage `json:"payload"`
+}
+
+// Recorder appends raw MQTT payloads to an NDJSON file for later replay.
+type Recorder struct {
+	mu   sync.Mutex
+	file *os.File
+	enc  *json.Encoder
+}
+
+// NewRecorder opens (or creates) the given file for appending recorded messages.
+func NewRecorder(path string) (*Recorder, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open record file: %w", err)
+	}
+
+	return &Recorder{
+		file: file,
+		enc:  json.NewEncoder(file),
+	}, nil
+}
+
+// Record appends a single message with the current timestamp.
+func (r *Recorder) Record(topic string, payload []byte) error {
+	// Keep non-JSON payloads replayable by wrapping them as a JSON string
+	raw := json.RawMessage(payload)
+	if !json.Valid(payload) {
+		quoted, err := json.Marshal(string(payload))
+		if err != nil {
+			return fmt.Errorf("failed to encode payload: %w", err)
+		}
+		raw = quoted
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.enc.Encode(RecordedMessage{
+		Timestamp: time.Now(),
+		Topic:     topic,
+		Payload:   raw,
+	})
+}
+
+// Close closes the underlying file.
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.file.Close()
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"bufio"
+	"controle-arcondicionado/internal/app"
+	"controle-arcondicionado/internal/rules"
+	"controle-arcondicionado/internal/thinq"
+	"encoding/json"
+	"flag"
+	"os"
+	"time"
+)
+
+func init() {
+	app.Register(app.Runner{
+		Name:     "replay",
+		Synopsis: "replay recorded MQTT events through the rules engine",
+		Run:      runReplay,
+	})
+}
+
+// dryRunClient logs the control calls the handler would have made instead of
+// sending them to the LG API. It is the default client during replay.
+type dryRunClient struct{}
+
+func (dryRunClient) SetTemperature(deviceID string, temperature int) error {
+	logMsg("[dry-run] Would set temperature of %s to %d°C", deviceID, temperature)
+	return nil
+}
+
+func (dryRunClient) SetPower(deviceID string, on bool) error {
+	logMsg("[dry-run] Would set power of %s to %v", deviceID, on)
+	return nil
+}
+
+func (dryRunClient) SetJobMode(deviceID string, mode string) error {
+	logMsg("[dry-run] Would set job mode of %s to %s", deviceID, mode)
+	return nil
+}
+
+func (dryRunClient) SetTwoSetTemperature(deviceID string, coolTarget, heatTarget int) error {
+	logMsg("[dry-run] Would set two-set range of %s to cool %d°C / heat %d°C", deviceID, coolTarget, heatTarget)
+	return nil
+}
+
+func (dryRunClient) SetWindStrength(deviceID string, strength string) error {
+	logMsg("[dry-run] Would set fan speed of %s to %s", deviceID, strength)
+	return nil
+}
+
+func (dryRunClient) SetPowerSave(deviceID string, on bool) error {
+	logMsg("[dry-run] Would set power save of %s to %v", deviceID, on)
+	return nil
+}
+
+func (dryRunClient) SetTargetHumidity(deviceID string, humidity int) error {
+	logMsg("[dry-run] Would set target humidity of %s to %d%%", deviceID, humidity)
+	return nil
+}
+
+// runReplay feeds a recorded NDJSON file through the message handler,
+// preserving (or accelerating) the original timing between messages.
+func runReplay(args []string) {
+	flags := flag.NewFlagSet("replay", flag.ExitOnError)
+	speed := flags.Float64("speed", 1.0, "playback speed multiplier (0 = no delays)")
+	live := flags.Bool("live", false, "send real control commands instead of dry-run")
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		logFatal("Usage: economizador replay [-speed N] [-live] <events.ndjson>")
+	}
+	path := flags.Arg(0)
+
+	// Load configuration (needed for the minimum temperature and, in live
+	// mode, for the ThinQ credentials)
+	boot, err := app.Load(logMsg)
+	if err != nil {
+		logFatal("%v", err)
+	}
+	cfg := boot.Config
+
+	var client rules.Controller = dryRunClient{}
+	var devices []thinq.Device
+	if *live {
+		devices, err = boot.Devices()
+		if err != nil {
+			logFatal("%v", err)
+		}
+		client = boot.Client
+	}
+	resolver := rules.NewResolver(cfg, devices)
+	engine := rules.NewEngine(client, resolver, devices, newNotifier(cfg), logMsg)
+
+	file, err := os.Open(path)
+	if err != nil {
+		logFatal("Failed to open replay file: %v", err)
+	}
+	defer file.Close()
+
+	logMsg("Replaying %s (speed: %.1fx, live: %v)", path, *speed, *live)
+
+	var lastTimestamp time.Time
+	count := 0
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		var msg RecordedMessage
+		if err := json.Unmarshal(line, &msg); err != nil {
+			logMsg("Skipping malformed line: %v", err)
+			continue
+		}
+
+		// Reproduce the original inter-message delay, scaled by speed
+		if *speed > 0 && !lastTimestamp.IsZero() {
+			delay := msg.Timestamp.Sub(lastTimestamp)
+			if delay > 0 {
+				time.Sleep(time.Duration(float64(delay) / *speed))
+			}
+		}
+		lastTimestamp = msg.Timestamp
+
+		processEvent(engine, msg.Payload)
+		count++
+	}
+	if err := scanner.Err(); err != nil {
+		logFatal("Failed to read replay file: %v", err)
+	}
+
+	logMsg("Replay complete: %d message(s) processed", count)
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"controle-arcondicionado/internal/app"
+	"controle-arcondicionado/internal/config"
+	"controle-arcondicionado/internal/history"
+	"controle-arcondicionado/internal/notify"
+	"controle-arcondicionado/internal/report"
+	"controle-arcondicionado/internal/sched"
+	"flag"
+	"fmt"
+	"time"
+)
+
+func init() {
+	app.Register(app.Runner{
+		Name:     "report",
+		Synopsis: "print a savings report from the history file",
+		Run:      runReportCommand,
+	})
+}
+
+// runReportCommand prints a savings report from the history file on demand.
+func runReportCommand(args []string) {
+	flags := flag.NewFlagSet("report", flag.ExitOnError)
+	period := flags.String("period", "day", "report period: day or week")
+	flags.Parse(args)
+
+	var span time.Duration
+	switch *period {
+	case "day":
+		span = 24 * time.Hour
+	case "week":
+		span = 7 * 24 * time.Hour
+	default:
+		logFatal("Invalid period %q (expected day or week)", *period)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		logFatal("Failed to load configuration: %v", err)
+	}
+	if cfg.HistoryFile == "off" {
+		logFatal("History is disabled (HISTORY_FILE=off)")
+	}
+
+	store, err := history.Open(cfg.HistoryFile)
+	if err != nil {
+		logFatal("Failed to open history: %v", err)
+	}
+	defer store.Close()
+
+	until := time.Now()
+	since := until.Add(-span)
+	entries, err := store.ReadSince(since)
+	if err != nil {
+		logFatal("Failed to read history: %v", err)
+	}
+
+	fmt.Print(report.Generate(entries, since, until, time.Time{}))
+}
+
+// startReportScheduler sends a daily report at cfg.ReportTime through the
+// notifier, plus a weekly report on Sunday. Returns a stop function.
+func startReportScheduler(cfg *config.Config, store *history.Store, notifier notify.Notifier, daemonStart time.Time) func() {
+	reportClock, err := time.Parse("15:04", cfg.ReportTime)
+	if err != nil {
+		logMsg("Warning: Invalid REPORT_TIME %q, reports disabled", cfg.ReportTime)
+		return func() {}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		for {
+			next := sched.NextAt(time.Now(), reportClock.Hour(), reportClock.Minute(), cfg.Location)
+
+			select {
+			case <-done:
+				return
+			case <-time.After(time.Until(next)):
+			}
+
+			sendScheduledReport(store, notifier, 24*time.Hour, "Daily savings report", daemonStart)
+			if time.Now().In(cfg.Location).Weekday() == time.Sunday {
+				sendScheduledReport(store, notifier, 7*24*time.Hour, "Weekly savings report", daemonStart)
+			}
+		}
+	}()
+
+	return func() { close(done) }
+}
+
+// sendScheduledReport generates a report over the given span and delivers it.
+func sendScheduledReport(store *history.Store, notifier notify.Notifier, span time.Duration, title string, daemonStart time.Time) {
+	until := time.Now()
+	since := until.Add(-span)
+	entries, err := store.ReadSince(since)
+	if err != nil {
+		logMsg("Failed to read history for report: %v", err)
+		return
+	}
+
+	text := report.Generate(entries, since, until, daemonStart)
+	logMsg("%s:\n%s", title, text)
+	if err := notifier.Send(title, text); err != nil {
+		logMsg("Failed to send report notification: %v", err)
+	}
+}
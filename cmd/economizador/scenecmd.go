@@ -0,0 +1,71 @@
+package main
+
+import (
+	"controle-arcondicionado/internal/app"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+func init() {
+	app.Register(app.Runner{
+		Name:     "scene",
+		Synopsis: "activate a configured scene on a running daemon",
+		Run:      runSceneCommand,
+	})
+}
+
+// runSceneCommand activates a named scene on a running daemon through its
+// local REST API. Without arguments it lists the configured scenes.
+func runSceneCommand(args []string) {
+	flags := flag.NewFlagSet("scene", flag.ExitOnError)
+	apiAddr := flags.String("api", defaultAPIAddr(), "address of the daemon's REST API")
+	flags.Parse(args)
+
+	if flags.NArg() > 1 {
+		logFatal("Usage: economizador scene [-api addr] [<name>]")
+	}
+
+	if flags.NArg() == 0 {
+		listScenes(*apiAddr)
+		return
+	}
+
+	name := flags.Arg(0)
+	url := fmt.Sprintf("http://%s/scenes/%s", *apiAddr, name)
+	resp, err := http.Post(url, "application/json", nil)
+	if err != nil {
+		logFatal("Failed to reach daemon API at %s: %v (is the daemon running?)", *apiAddr, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		logFatal("API returned status %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	logMsg("Scene %q activated", name)
+}
+
+// listScenes prints the scene names configured on the daemon.
+func listScenes(apiAddr string) {
+	resp, err := http.Get(fmt.Sprintf("http://%s/scenes", apiAddr))
+	if err != nil {
+		logFatal("Failed to reach daemon API at %s: %v (is the daemon running?)", apiAddr, err)
+	}
+	defer resp.Body.Close()
+
+	var names []string
+	if err := json.NewDecoder(resp.Body).Decode(&names); err != nil {
+		logFatal("Failed to parse API response: %v", err)
+	}
+
+	if len(names) == 0 {
+		logMsg("No scenes configured (add a scenes section to the config file)")
+		return
+	}
+	logMsg("Configured scenes: %s", strings.Join(names, ", "))
+}
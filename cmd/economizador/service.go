@@ -0,0 +1,104 @@
+package main
+
+import (
+	"controle-arcondicionado/internal/app"
+	"controle-arcondicionado/internal/systemd"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+)
+
+func init() {
+	app.Register(app.Runner{
+		Name:     "install-service",
+		Synopsis: "install the daemon as a systemd service",
+		Run:      runInstallService,
+	})
+}
+
+// unitTemplate is the systemd unit written by the install-service command.
+const unitTemplate = `[Unit]
+Description=LG ThinQ Energy Saver
+After=network-online.target
+Wants=network-online.target
+
+[Service]
+Type=notify
+ExecStart=%s
+WorkingDirectory=%s
+WatchdogSec=120
+Restart=on-failure
+RestartSec=10
+
+[Install]
+WantedBy=multi-user.target
+`
+
+// startWatchdog sends systemd watchdog keepalives while the MQTT connection
+// is healthy. Skipping keepalives when the connection drops lets systemd
+// restart a daemon that lost its broker and never recovered.
+func startWatchdog(mqttClient mqtt.Client) (stop func()) {
+	interval, enabled := systemd.WatchdogInterval()
+	if !enabled {
+		return func() {}
+	}
+	logMsg("Systemd watchdog enabled, keepalive every %s", interval)
+
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if !mqttClient.IsConnectionOpen() {
+					logMsg("Skipping watchdog keepalive: MQTT connection is down")
+					continue
+				}
+				if err := systemd.NotifyWatchdog(); err != nil {
+					logMsg("Warning: Failed to send watchdog keepalive: %v", err)
+				}
+			}
+		}
+	}()
+
+	return func() { close(done) }
+}
+
+// runInstallService writes a systemd unit file pointing at the current
+// binary and working directory.
+func runInstallService(args []string) {
+	flags := flag.NewFlagSet("install-service", flag.ExitOnError)
+	unitPath := flags.String("path", "/etc/systemd/system/economizador.service", "where to write the unit file")
+	flags.Parse(args)
+
+	executable, err := os.Executable()
+	if err != nil {
+		logFatal("Failed to determine executable path: %v", err)
+	}
+	executable, err = filepath.EvalSymlinks(executable)
+	if err != nil {
+		logFatal("Failed to resolve executable path: %v", err)
+	}
+
+	workDir, err := os.Getwd()
+	if err != nil {
+		logFatal("Failed to determine working directory: %v", err)
+	}
+
+	unit := fmt.Sprintf(unitTemplate, executable, workDir)
+	if err := os.WriteFile(*unitPath, []byte(unit), 0644); err != nil {
+		logFatal("Failed to write unit file: %v", err)
+	}
+
+	logMsg("Wrote systemd unit to %s", *unitPath)
+	logMsg("Enable it with:")
+	logMsg("  sudo systemctl daemon-reload")
+	logMsg("  sudo systemctl enable --now economizador")
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"bufio"
+	"controle-arcondicionado/internal/app"
+	"controle-arcondicionado/internal/config"
+	"controle-arcondicionado/internal/thinq"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"gopkg.in/yaml.v3"
+)
+
+func init() {
+	app.Register(app.Runner{
+		Name:     "setup",
+		Synopsis: "interactive first-run setup wizard",
+		Run:      runSetup,
+	})
+}
+
+// runSetup walks the user through entering their PAT, choosing a country,
+// discovering devices, picking which to manage and setting minimum
+// temperatures, then writes .env and config.yaml.
+func runSetup(args []string) {
+	input := bufio.NewScanner(os.Stdin)
+	ask := func(prompt, fallback string) string {
+		if fallback != "" {
+			fmt.Printf("%s [%s]: ", prompt, fallback)
+		} else {
+			fmt.Printf("%s: ", prompt)
+		}
+		if !input.Scan() {
+			fmt.Println()
+			os.Exit(1)
+		}
+		answer := strings.TrimSpace(input.Text())
+		if answer == "" {
+			return fallback
+		}
+		return answer
+	}
+
+	fmt.Println("=== LG ThinQ Energy Saver setup ===")
+	fmt.Println()
+
+	if _, err := os.Stat(".env"); err == nil {
+		if !strings.EqualFold(ask("A .env file already exists. Overwrite it? (y/N)", "n"), "y") {
+			fmt.Println("Keeping the existing configuration. Nothing written.")
+			return
+		}
+	}
+
+	fmt.Println("Generate a Personal Access Token at https://connect-pat.lgthinq.com/")
+	pat := ask("Personal Access Token", "")
+	for pat == "" {
+		pat = ask("Personal Access Token", "")
+	}
+
+	country := strings.ToUpper(ask("Country code", "BR"))
+	for !thinqCountries[country] {
+		fmt.Printf("%q is not a known ThinQ region.\n", country)
+		country = strings.ToUpper(ask("Country code", "BR"))
+	}
+
+	fmt.Println("\nConnecting to LG to discover your devices...")
+	client := thinq.NewClient(pat, country, uuid.New().String())
+	devices, err := client.GetDeviceList()
+	if err != nil {
+		logFatal("Could not list devices (check the token and country): %v", err)
+	}
+	if len(devices) == 0 {
+		logFatal("The account has no registered devices; add them in the ThinQ app first")
+	}
+
+	fmt.Printf("Found %d device(s):\n", len(devices))
+	for i, device := range devices {
+		fmt.Printf("  %d. %s\n", i+1, device.Alias)
+	}
+
+	managed := pickDevices(ask, devices)
+
+	minTemp := askInt(ask, "Minimum allowed temperature (°C)", 21)
+	if minTemp < 21 {
+		fmt.Println("The daemon enforces at least 21°C; using 21.")
+		minTemp = 21
+	}
+
+	// Per-device minimums become overrides; unselected devices are
+	// observed but never corrected (mode: log)
+	var overrides []config.DeviceOverride
+	for _, device := range devices {
+		if !managed[device.DeviceID] {
+			overrides = append(overrides, config.DeviceOverride{
+				Name:   device.Alias,
+				Policy: config.Policy{Mode: "log"},
+			})
+			continue
+		}
+		deviceMin := askInt(ask, fmt.Sprintf("Minimum for %s (°C)", device.Alias), minTemp)
+		if deviceMin != minTemp {
+			overrides = append(overrides, config.DeviceOverride{
+				Name:   device.Alias,
+				Policy: config.Policy{MinTemperature: &deviceMin},
+			})
+		}
+	}
+
+	env := fmt.Sprintf("THINQ_PAT=%s\nTHINQ_COUNTRY_CODE=%s\nMIN_TEMPERATURE=%d\n", pat, country, minTemp)
+	if err := os.WriteFile(".env", []byte(env), 0600); err != nil {
+		logFatal("Failed to write .env: %v", err)
+	}
+	fmt.Println("\nWrote .env")
+
+	if len(overrides) > 0 {
+		data, err := yaml.Marshal(fileConfigForSetup{Devices: overrides})
+		if err != nil {
+			logFatal("Failed to encode config file: %v", err)
+		}
+		if err := os.WriteFile(config.DefaultConfigFile, data, 0644); err != nil {
+			logFatal("Failed to write %s: %v", config.DefaultConfigFile, err)
+		}
+		fmt.Printf("Wrote %s\n", config.DefaultConfigFile)
+	}
+
+	fmt.Println("\nAll set! Start the daemon with: economizador")
+}
+
+// fileConfigForSetup is the subset of the YAML config the wizard writes.
+type fileConfigForSetup struct {
+	Devices []config.DeviceOverride `yaml:"devices"`
+}
+
+// pickDevices asks which devices to manage and returns their IDs.
+func pickDevices(ask func(prompt, fallback string) string, devices []thinq.Device) map[string]bool {
+	managed := make(map[string]bool)
+	for {
+		answer := ask("Devices to manage (numbers separated by comma)", "all")
+		if strings.EqualFold(answer, "all") {
+			for _, device := range devices {
+				managed[device.DeviceID] = true
+			}
+			return managed
+		}
+
+		valid := true
+		picked := make(map[string]bool)
+		for _, field := range strings.Split(answer, ",") {
+			index, err := strconv.Atoi(strings.TrimSpace(field))
+			if err != nil || index < 1 || index > len(devices) {
+				fmt.Printf("Invalid selection %q.\n", strings.TrimSpace(field))
+				valid = false
+				break
+			}
+			picked[devices[index-1].DeviceID] = true
+		}
+		if valid && len(picked) > 0 {
+			return picked
+		}
+	}
+}
+
+// askInt prompts for an integer, re-asking until the answer parses.
+func askInt(ask func(prompt, fallback string) string, prompt string, fallback int) int {
+	for {
+		answer := ask(prompt, strconv.Itoa(fallback))
+		value, err := strconv.Atoi(answer)
+		if err == nil {
+			return value
+		}
+		fmt.Printf("%q is not a number.\n", answer)
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"controle-arcondicionado/internal/app"
+	"controle-arcondicionado/internal/config"
+	"controle-arcondicionado/internal/notify"
+	"controle-arcondicionado/internal/rules"
+	"controle-arcondicionado/internal/sim"
+	"controle-arcondicionado/internal/thinq"
+	"flag"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+func init() {
+	app.Register(app.Runner{
+		Name:     "simulate",
+		Synopsis: "run the enforcement pipeline against a simulated household",
+		Run:      runSimulate,
+	})
+}
+
+// runSimulate spins up the mock ThinQ server with virtual air conditioners
+// and runs the real enforcement pipeline against it — no LG account, no
+// .env needed. Useful to watch policy changes act on misbehaving "users".
+func runSimulate(args []string) {
+	flags := flag.NewFlagSet("simulate", flag.ExitOnError)
+	count := flags.Int("n", 3, "number of virtual air conditioners")
+	minTemp := flags.Int("min", 21, "minimum temperature to enforce")
+	duration := flags.Duration("duration", 0, "stop after this long (0 = run until Ctrl+C)")
+	flags.Parse(args)
+
+	household := sim.NewHousehold(*count, logMsg)
+	defer household.Close()
+	logMsg("Simulated household started with %d device(s) (mock API at %s)", *count, household.URL())
+
+	// The real client, pointed at the mock server
+	client := thinq.NewClient("simulated-pat", "BR", "simulator")
+	client.SetBaseURL(household.URL())
+
+	devices, err := client.GetDeviceList()
+	if err != nil {
+		logFatal("Failed to list simulated devices: %v", err)
+	}
+
+	cfg := &config.Config{MinTemperature: *minTemp}
+	resolver := rules.NewResolver(cfg, devices)
+	engine := rules.NewEngine(client, resolver, devices, &notify.LogNotifier{Logf: logMsg}, logMsg)
+
+	logMsg("Enforcing a minimum of %d°C (press Ctrl+C to stop)...", *minTemp)
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+
+	var timeout <-chan time.Time
+	if *duration > 0 {
+		timeout = time.After(*duration)
+	}
+
+	for {
+		select {
+		case payload := <-household.Events():
+			processEvent(engine, payload)
+		case <-timeout:
+			logMsg("Simulation finished after %s", *duration)
+			return
+		case <-stop:
+			logMsg("Simulation stopped")
+			return
+		}
+	}
+}
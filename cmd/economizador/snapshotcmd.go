@@ -0,0 +1,166 @@
+package main
+
+import (
+	"controle-arcondicionado/internal/app"
+	"controle-arcondicionado/internal/thinq"
+	"encoding/json"
+	"flag"
+	"os"
+	"time"
+)
+
+func init() {
+	app.Register(app.Runner{
+		Name:     "snapshot",
+		Synopsis: "save or restore every device's current settings",
+		Run:      runSnapshotCommand,
+	})
+}
+
+// deviceSnapshot captures one device's settings. Fields the device did not
+// report are omitted and left untouched on restore.
+type deviceSnapshot struct {
+	Name        string `json:"name"`
+	Power       *bool  `json:"power,omitempty"`
+	Mode        string `json:"mode,omitempty"`
+	Temperature *int   `json:"temperature,omitempty"`
+	Fan         string `json:"fan,omitempty"`
+}
+
+// snapshotFile is the on-disk shape of a settings snapshot.
+type snapshotFile struct {
+	SavedAt time.Time        `json:"saved_at"`
+	Devices []deviceSnapshot `json:"devices"`
+}
+
+// runSnapshotCommand implements `economizador snapshot save|restore`,
+// capturing every device's power, mode, target and fan to a JSON file and
+// putting them back later — handy before away mode or policy experiments.
+func runSnapshotCommand(args []string) {
+	if len(args) < 1 || (args[0] != "save" && args[0] != "restore") {
+		logFatal("Usage: economizador snapshot <save|restore> [-f snapshot.json]")
+	}
+	action := args[0]
+
+	flags := flag.NewFlagSet("snapshot "+action, flag.ExitOnError)
+	path := flags.String("f", "snapshot.json", "path of the snapshot file")
+	flags.Parse(args[1:])
+
+	boot, err := app.Load(logMsg)
+	if err != nil {
+		logFatal("%v", err)
+	}
+
+	if action == "save" {
+		saveSnapshot(boot, *path)
+	} else {
+		restoreSnapshot(boot, *path)
+	}
+}
+
+// saveSnapshot polls every device's state and writes the snapshot file.
+func saveSnapshot(boot *app.Bootstrap, path string) {
+	devices, err := boot.Devices()
+	if err != nil {
+		logFatal("%v", err)
+	}
+
+	snapshot := snapshotFile{SavedAt: time.Now()}
+	for _, device := range devices {
+		state, err := boot.Client.GetDeviceState(device.DeviceID)
+		if err != nil {
+			logMsg("[%s] Skipping: failed to read state: %v", device.Alias, err)
+			continue
+		}
+		snapshot.Devices = append(snapshot.Devices, captureDevice(device.Alias, state))
+		logMsg("[%s] Captured", device.Alias)
+	}
+	if len(snapshot.Devices) == 0 {
+		logFatal("No device state could be captured")
+	}
+
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		logFatal("Failed to encode snapshot: %v", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		logFatal("Failed to write snapshot: %v", err)
+	}
+	logMsg("Snapshot of %d device(s) saved to %s", len(snapshot.Devices), path)
+}
+
+// captureDevice extracts the restorable settings from a state document.
+func captureDevice(alias string, state map[string]interface{}) deviceSnapshot {
+	captured := deviceSnapshot{Name: alias}
+
+	if operation, ok := state["operation"].(map[string]interface{}); ok {
+		if mode, ok := operation["airConOperationMode"].(string); ok {
+			powerOn := mode == "POWER_ON"
+			captured.Power = &powerOn
+		}
+	}
+	if jobMode, ok := state["airConJobMode"].(map[string]interface{}); ok {
+		captured.Mode, _ = jobMode["currentJobMode"].(string)
+	}
+	if temperature, ok := state["temperature"].(map[string]interface{}); ok {
+		if target, ok := temperature["targetTemperature"].(float64); ok {
+			targetInt := int(target)
+			captured.Temperature = &targetInt
+		}
+	}
+	if airFlow, ok := state["airFlow"].(map[string]interface{}); ok {
+		captured.Fan, _ = airFlow["windStrength"].(string)
+	}
+
+	return captured
+}
+
+// restoreSnapshot reads the snapshot file and applies it device by device.
+func restoreSnapshot(boot *app.Bootstrap, path string) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		logFatal("Failed to read snapshot: %v", err)
+	}
+	var snapshot snapshotFile
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		logFatal("Failed to parse snapshot %s: %v", path, err)
+	}
+	if len(snapshot.Devices) == 0 {
+		logFatal("Snapshot %s has no devices", path)
+	}
+
+	devices, err := boot.Devices()
+	if err != nil {
+		logFatal("%v", err)
+	}
+
+	logMsg("Restoring snapshot from %s (saved %s)", path, snapshot.SavedAt.Format("2006-01-02 15:04"))
+	failures := 0
+	for _, captured := range snapshot.Devices {
+		deviceID, err := thinq.ResolveDevice(devices, captured.Name)
+		if err != nil {
+			logMsg("[%s] FAILED: %v", captured.Name, err)
+			failures++
+			continue
+		}
+
+		step := controlStep{
+			Name:        captured.Name,
+			Power:       captured.Power,
+			Mode:        captured.Mode,
+			Temperature: captured.Temperature,
+			Fan:         captured.Fan,
+		}
+		if err := applyStep(boot.Client, deviceID, step); err != nil {
+			logMsg("[%s] FAILED: %v", captured.Name, err)
+			failures++
+			continue
+		}
+		logMsg("[%s] OK", captured.Name)
+	}
+
+	if failures > 0 {
+		logFatal("Snapshot restore finished with %d failure(s)", failures)
+	}
+	logMsg("Snapshot restored successfully!")
+}
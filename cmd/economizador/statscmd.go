@@ -0,0 +1,61 @@
+package main
+
+import (
+	"controle-arcondicionado/internal/app"
+	"controle-arcondicionado/internal/statecache"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+)
+
+func init() {
+	app.Register(app.Runner{
+		Name:     "stats",
+		Synopsis: "show per-device activity counters from a running daemon",
+		Run:      runStatsCommand,
+	})
+}
+
+// runStatsCommand fetches the per-device counters from a running daemon's
+// REST API and renders them as a table, answering "which room misbehaves
+// the most" without grepping the log.
+func runStatsCommand(args []string) {
+	flags := flag.NewFlagSet("stats", flag.ExitOnError)
+	apiAddr := flags.String("api", defaultAPIAddr(), "address of the daemon's REST API")
+	flags.Parse(args)
+
+	url := fmt.Sprintf("http://%s/stats", *apiAddr)
+	resp, err := http.Get(url)
+	if err != nil {
+		logFatal("Failed to reach daemon API at %s: %v (is the daemon running?)", *apiAddr, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		logFatal("API returned status %d", resp.StatusCode)
+	}
+
+	var stats []statecache.DeviceStats
+	if err := json.NewDecoder(resp.Body).Decode(&stats); err != nil {
+		logFatal("Failed to parse API response: %v", err)
+	}
+	if len(stats) == 0 {
+		logMsg("No devices known to the daemon")
+		return
+	}
+
+	fmt.Printf("%-20s %8s %12s %10s %9s\n", "DEVICE", "EVENTS", "CORRECTIONS", "ON TODAY", "AVG SET")
+	for _, device := range stats {
+		name := device.Alias
+		if name == "" {
+			name = device.DeviceID
+		}
+		avgSetpoint := "-"
+		if device.AvgSetpoint > 0 {
+			avgSetpoint = fmt.Sprintf("%.1f°C", device.AvgSetpoint)
+		}
+		fmt.Printf("%-20s %8d %12d %9dm %9s\n",
+			name, device.Events, device.Corrections, device.MinutesOnToday, avgSetpoint)
+	}
+}
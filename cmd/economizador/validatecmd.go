@@ -0,0 +1,127 @@
+package main
+
+import (
+	"controle-arcondicionado/internal/app"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+func init() {
+	app.Register(app.Runner{
+		Name:     "validate-config",
+		Synopsis: "check the configuration and LG credentials",
+		Run:      runValidateConfig,
+	})
+}
+
+// thinqCountries are the country codes LG ThinQ accepts. Derived from the
+// regions the ThinQ Connect service is offered in.
+var thinqCountries = map[string]bool{
+	"AR": true, "AU": true, "AT": true, "BE": true, "BR": true, "CA": true,
+	"CL": true, "CN": true, "CO": true, "CZ": true, "DE": true, "DK": true,
+	"ES": true, "FI": true, "FR": true, "GB": true, "GR": true, "HK": true,
+	"HU": true, "ID": true, "IE": true, "IL": true, "IN": true, "IT": true,
+	"JP": true, "KR": true, "MX": true, "MY": true, "NL": true, "NO": true,
+	"NZ": true, "PA": true, "PE": true, "PH": true, "PL": true, "PT": true,
+	"RO": true, "RU": true, "SA": true, "SE": true, "SG": true, "TH": true,
+	"TR": true, "TW": true, "UA": true, "US": true, "VN": true, "ZA": true,
+}
+
+// runValidateConfig loads the configuration, verifies the credentials
+// against the LG API and cross-checks zones, overrides and scenes against
+// the real device list, printing a readable report.
+func runValidateConfig(args []string) {
+	flags := flag.NewFlagSet("validate-config", flag.ExitOnError)
+	flags.Parse(args)
+
+	failures := 0
+	fail := func(format string, a ...interface{}) {
+		fmt.Printf("FAIL  %s\n", fmt.Sprintf(format, a...))
+		failures++
+	}
+	warn := func(format string, a ...interface{}) {
+		fmt.Printf("WARN  %s\n", fmt.Sprintf(format, a...))
+	}
+	ok := func(format string, a ...interface{}) {
+		fmt.Printf("ok    %s\n", fmt.Sprintf(format, a...))
+	}
+
+	boot, err := app.Load(func(string, ...interface{}) {})
+	if err != nil {
+		fail("%v", err)
+		fmt.Println("\nConfiguration could not be loaded; nothing else to check.")
+		os.Exit(1)
+	}
+	cfg := boot.Config
+	ok("configuration loaded")
+
+	// Country code
+	if thinqCountries[strings.ToUpper(cfg.CountryCode)] {
+		ok("country code %s is a known ThinQ region", cfg.CountryCode)
+	} else {
+		fail("country code %q is not a known ThinQ region (e.g. BR, US, DE)", cfg.CountryCode)
+	}
+
+	// PAT: the only reliable check is calling the API with it
+	devices, err := boot.Devices()
+	if err != nil {
+		if strings.Contains(err.Error(), "401") {
+			fail("LG rejected the access token (401): generate a new PAT at https://connect-pat.lgthinq.com/ and update THINQ_PAT")
+		} else {
+			fail("could not reach the LG API: %v", err)
+		}
+		fmt.Printf("\n%d problem(s) found.\n", failures)
+		os.Exit(1)
+	}
+	ok("access token accepted; account has %d device(s)", len(devices))
+	if len(devices) == 0 {
+		warn("no devices registered on this account")
+	}
+
+	aliases := make(map[string]bool)
+	ids := make(map[string]bool)
+	for _, device := range devices {
+		aliases[device.Alias] = true
+		ids[device.DeviceID] = true
+	}
+	known := func(name string) bool { return aliases[name] || ids[name] }
+
+	// Zones and per-device overrides must reference real devices
+	zones := make(map[string]bool)
+	for _, zone := range cfg.Zones {
+		zones[zone.Name] = true
+		for _, name := range zone.Devices {
+			if !known(name) {
+				fail("zone %q references unknown device %q", zone.Name, name)
+			}
+		}
+	}
+	for _, override := range cfg.DeviceOverrides {
+		if !known(override.Name) {
+			fail("device override references unknown device %q", override.Name)
+		}
+	}
+	if len(cfg.Zones) > 0 || len(cfg.DeviceOverrides) > 0 {
+		ok("checked %d zone(s) and %d device override(s)", len(cfg.Zones), len(cfg.DeviceOverrides))
+	}
+
+	// Scene steps may name a device or a zone
+	for name, scene := range cfg.Scenes {
+		for _, step := range scene.Devices {
+			if !known(step.Name) && !zones[step.Name] {
+				fail("scene %q references unknown device or zone %q", name, step.Name)
+			}
+		}
+	}
+	if len(cfg.Scenes) > 0 {
+		ok("checked %d scene(s)", len(cfg.Scenes))
+	}
+
+	if failures > 0 {
+		fmt.Printf("\n%d problem(s) found.\n", failures)
+		os.Exit(1)
+	}
+	fmt.Println("\nConfiguration is valid.")
+}
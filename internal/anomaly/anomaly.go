@@ -0,0 +1,136 @@
+// Package anomaly watches the rate of change of the reported room
+// temperature and alerts when a unit runs in cooling mode without the room
+// actually cooling down — typically an open window, a dirty filter or a
+// failing compressor.
+package anomaly
+
+import (
+	"controle-arcondicionado/internal/notify"
+	"controle-arcondicionado/internal/thinq"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// alertCooldown suppresses repeated alerts for the same device.
+const alertCooldown = time.Hour
+
+// sample is one reported room temperature at a point in time.
+type sample struct {
+	at   time.Time
+	temp float64
+}
+
+// Detector accumulates temperature samples per device while the unit is
+// cooling and raises a notification when, after the configured window, the
+// room has not cooled by at least the configured drop.
+type Detector struct {
+	mu       sync.Mutex
+	notifier notify.Notifier
+	aliases  map[string]string
+	window   time.Duration
+	minDrop  float64
+	samples  map[string][]sample
+	jobModes map[string]string // last reported job mode per device
+	alerted  map[string]time.Time
+	logf     func(format string, args ...interface{})
+}
+
+// NewDetector builds a detector with the given analysis window and minimum
+// expected temperature drop over that window.
+func NewDetector(devices []thinq.Device, notifier notify.Notifier, window time.Duration, minDrop float64, logf func(format string, args ...interface{})) *Detector {
+	aliases := make(map[string]string)
+	for _, device := range devices {
+		aliases[device.DeviceID] = device.Alias
+	}
+	return &Detector{
+		notifier: notifier,
+		aliases:  aliases,
+		window:   window,
+		minDrop:  minDrop,
+		samples:  make(map[string][]sample),
+		jobModes: make(map[string]string),
+		alerted:  make(map[string]time.Time),
+		logf:     logf,
+	}
+}
+
+// Observe feeds one event from the pipeline into the detector.
+func (d *Detector) Observe(event *thinq.Event) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	// Power-off ends the analysis window for the device
+	if event.PowerOn != nil && !*event.PowerOn {
+		delete(d.samples, event.DeviceID)
+		return
+	}
+
+	// Only cooling modes are expected to lower the room temperature.
+	// Events are partial updates, so remember the last reported mode: a
+	// unit left in FAN keeps discarding samples even when later events
+	// carry only temperatures.
+	if event.JobMode != "" {
+		d.jobModes[event.DeviceID] = event.JobMode
+	}
+	switch d.jobModes[event.DeviceID] {
+	case "", "COOL", "AUTO", "AIR_DRY", "JET":
+	default:
+		delete(d.samples, event.DeviceID)
+		return
+	}
+
+	if event.CurrentTemperature == nil {
+		return
+	}
+
+	now := time.Now()
+	samples := append(d.samples[event.DeviceID], sample{at: now, temp: *event.CurrentTemperature})
+
+	// Keep only the analysis window (plus the sample just before it, so
+	// the window is always fully covered)
+	for len(samples) > 1 && now.Sub(samples[1].at) >= d.window {
+		samples = samples[1:]
+	}
+	d.samples[event.DeviceID] = samples
+
+	oldest := samples[0]
+	if now.Sub(oldest.at) < d.window {
+		return // not enough history yet
+	}
+
+	drop := oldest.temp - *event.CurrentTemperature
+	if drop >= d.minDrop {
+		delete(d.alerted, event.DeviceID)
+		return
+	}
+
+	// Already at or below the target: nothing left to cool
+	if target := coolTarget(event); target != nil && *event.CurrentTemperature <= *target {
+		return
+	}
+
+	if last, ok := d.alerted[event.DeviceID]; ok && now.Sub(last) < alertCooldown {
+		return
+	}
+	d.alerted[event.DeviceID] = now
+
+	alias := d.aliases[event.DeviceID]
+	if alias == "" {
+		alias = event.DeviceID
+	}
+	message := fmt.Sprintf("%s has been cooling for %d minutes but the room went from %.1f°C to %.1f°C. Check for open windows or a failing unit.",
+		alias, int(d.window.Minutes()), oldest.temp, *event.CurrentTemperature)
+	d.logf("Anomaly: %s", message)
+	if err := d.notifier.Send("Room not cooling", message); err != nil {
+		d.logf("Failed to send anomaly notification: %v", err)
+	}
+}
+
+// coolTarget returns the effective cooling target of the event, if any.
+func coolTarget(event *thinq.Event) *float64 {
+	if event.CoolTarget != nil {
+		return event.CoolTarget
+	}
+	return event.TargetTemperature
+}
@@ -0,0 +1,236 @@
+// Package api exposes the daemon's local REST interface, used by the CLI
+// and other tools to inspect devices and change behavior at runtime.
+package api
+
+import (
+	"controle-arcondicionado/internal/rules"
+	"controle-arcondicionado/internal/statecache"
+	"controle-arcondicionado/internal/thinq"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Server is the daemon's local HTTP API.
+type Server struct {
+	engine  *rules.Engine
+	devices []thinq.Device
+	metrics http.Handler      // optional Prometheus endpoint
+	states  *statecache.Cache // optional last-known-state cache
+	logf    func(format string, args ...interface{})
+}
+
+// NewServer creates an API server around the rules engine.
+func NewServer(engine *rules.Engine, devices []thinq.Device, logf func(format string, args ...interface{})) *Server {
+	return &Server{
+		engine:  engine,
+		devices: devices,
+		logf:    logf,
+	}
+}
+
+// SetMetrics exposes a metrics handler under /metrics. Must be called
+// before Start.
+func (s *Server) SetMetrics(handler http.Handler) {
+	s.metrics = handler
+}
+
+// SetStateCache exposes the last-known-state cache under /state. Must be
+// called before Start.
+func (s *Server) SetStateCache(states *statecache.Cache) {
+	s.states = states
+}
+
+// Start begins serving on the given address in a background goroutine.
+func (s *Server) Start(listen string) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /devices", s.handleListDevices)
+	if s.states != nil {
+		mux.HandleFunc("GET /state", s.handleState)
+		mux.HandleFunc("GET /state/{device}", s.handleDeviceState)
+		mux.HandleFunc("GET /stats", s.handleStats)
+	}
+	mux.HandleFunc("PUT /devices/{device}/mode", s.handleSetMode)
+	mux.HandleFunc("GET /scenes", s.handleListScenes)
+	mux.HandleFunc("POST /scenes/{scene}", s.handleApplyScene)
+	mux.HandleFunc("GET /away", s.handleGetAway)
+	mux.HandleFunc("POST /away", s.handleSetAway)
+	mux.HandleFunc("DELETE /away", s.handleClearAway)
+	if s.metrics != nil {
+		mux.Handle("GET /metrics", s.metrics)
+	}
+
+	server := &http.Server{
+		Addr:        listen,
+		Handler:     mux,
+		ReadTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			s.logf("API server error: %v", err)
+		}
+	}()
+
+	s.logf("API listening on http://%s", listen)
+	return nil
+}
+
+// deviceView is the JSON representation of a device and its policy.
+type deviceView struct {
+	DeviceID       string `json:"deviceId"`
+	Alias          string `json:"alias"`
+	Zone           string `json:"zone,omitempty"`
+	MinTemperature int    `json:"minTemperature"`
+	Mode           string `json:"mode"`
+	Error          string `json:"error,omitempty"` // active fault code, if any
+}
+
+func (s *Server) handleListDevices(w http.ResponseWriter, r *http.Request) {
+	deviceErrors := s.engine.DeviceErrors()
+	views := make([]deviceView, 0, len(s.devices))
+	for _, device := range s.devices {
+		policy := s.engine.Resolver().PolicyFor(device.DeviceID)
+		views = append(views, deviceView{
+			DeviceID:       device.DeviceID,
+			Alias:          device.Alias,
+			Zone:           s.engine.Resolver().ZoneOf(device.DeviceID),
+			MinTemperature: policy.MinTemperature,
+			Mode:           string(s.engine.ModeOf(device.DeviceID)),
+			Error:          deviceErrors[device.DeviceID],
+		})
+	}
+	writeJSON(w, http.StatusOK, views)
+}
+
+func (s *Server) handleState(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.states.All())
+}
+
+func (s *Server) handleDeviceState(w http.ResponseWriter, r *http.Request) {
+	deviceID, err := s.resolveDevice(r.PathValue("device"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	state, ok := s.states.Get(deviceID)
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("no state for device %s", deviceID))
+		return
+	}
+	writeJSON(w, http.StatusOK, state)
+}
+
+func (s *Server) handleStats(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.states.Stats())
+}
+
+func (s *Server) handleSetMode(w http.ResponseWriter, r *http.Request) {
+	deviceID, err := s.resolveDevice(r.PathValue("device"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	var body struct {
+		Mode string `json:"mode"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return
+	}
+
+	mode, err := rules.ParseMode(body.Mode)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	s.engine.SetMode(deviceID, mode)
+	writeJSON(w, http.StatusOK, map[string]string{
+		"deviceId": deviceID,
+		"mode":     string(mode),
+	})
+}
+
+func (s *Server) handleListScenes(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.engine.Scenes())
+}
+
+func (s *Server) handleApplyScene(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("scene")
+	if err := s.engine.ApplyScene(name); err != nil {
+		status := http.StatusBadGateway
+		if strings.HasPrefix(err.Error(), "unknown scene") {
+			status = http.StatusNotFound
+		}
+		writeError(w, status, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"scene": name})
+}
+
+// awayView is the JSON representation of the away mode state.
+type awayView struct {
+	Away  bool   `json:"away"`
+	Until string `json:"until,omitempty"`
+}
+
+func (s *Server) handleGetAway(w http.ResponseWriter, r *http.Request) {
+	away, until := s.engine.AwayStatus()
+	view := awayView{Away: away}
+	if !until.IsZero() {
+		view.Until = until.Format(time.RFC3339)
+	}
+	writeJSON(w, http.StatusOK, view)
+}
+
+func (s *Server) handleSetAway(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Until   string `json:"until"` // RFC3339, optional
+		Precool bool   `json:"precool"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return
+	}
+
+	policy := rules.AwayPolicy{PowerOff: true}
+	if body.Until == "" {
+		s.engine.SetAway(true, policy)
+	} else {
+		until, err := time.Parse(time.RFC3339, body.Until)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid until time: %w", err))
+			return
+		}
+		if err := s.engine.SetAwayUntil(until, body.Precool, policy); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	s.handleGetAway(w, r)
+}
+
+func (s *Server) handleClearAway(w http.ResponseWriter, r *http.Request) {
+	s.engine.SetAway(false, rules.AwayPolicy{})
+	s.handleGetAway(w, r)
+}
+
+// resolveDevice accepts a device ID, an alias or a unique alias prefix.
+func (s *Server) resolveDevice(nameOrID string) (string, error) {
+	return thinq.ResolveDevice(s.devices, nameOrID)
+}
+
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(value)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,43 @@
+// Package app hosts the runner registry and the bootstrap shared by every
+// mode of the economizador binary. New modes (monitor, exporter, ...)
+// register themselves as runners instead of duplicating the config, client
+// and MQTT setup of main.go.
+package app
+
+import "sort"
+
+// Runner is one mode of the binary (daemon, dashboard, replay, ...).
+type Runner struct {
+	Name     string
+	Synopsis string
+	Run      func(args []string)
+}
+
+var runners = make(map[string]Runner)
+
+// Register adds a runner to the registry. Called from init functions of
+// the files implementing each mode.
+func Register(runner Runner) {
+	runners[runner.Name] = runner
+}
+
+// Lookup returns the runner registered under the given name.
+func Lookup(name string) (Runner, bool) {
+	runner, ok := runners[name]
+	return runner, ok
+}
+
+// Runners returns every registered runner, sorted by name.
+func Runners() []Runner {
+	names := make([]string, 0, len(runners))
+	for name := range runners {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	sorted := make([]Runner, 0, len(names))
+	for _, name := range names {
+		sorted = append(sorted, runners[name])
+	}
+	return sorted
+}
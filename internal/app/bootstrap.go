@@ -0,0 +1,59 @@
+package app
+
+import (
+	"controle-arcondicionado/internal/config"
+	"controle-arcondicionado/internal/thinq"
+	"fmt"
+)
+
+// Bootstrap bundles what most runners need to talk to LG: the loaded
+// configuration, a ThinQ client and (lazily) the account's device list.
+type Bootstrap struct {
+	Config *config.Config
+	Client *thinq.Client
+	Logf   func(format string, args ...interface{})
+
+	devices []thinq.Device
+}
+
+// Load builds the shared bootstrap: configuration and ThinQ client.
+func Load(logf func(format string, args ...interface{})) (*Bootstrap, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	client := thinq.NewClient(cfg.ThinQPAT, cfg.CountryCode, cfg.ClientID)
+	if logf != nil {
+		client.SetLogf(logf)
+	}
+	if err := client.ConfigureHTTP(thinq.HTTPOptions{
+		Timeout:               cfg.HTTPTimeout,
+		DialTimeout:           cfg.HTTPDialTimeout,
+		TLSHandshakeTimeout:   cfg.HTTPTLSTimeout,
+		ResponseHeaderTimeout: cfg.HTTPHeaderTimeout,
+		ProxyURL:              cfg.HTTPProxyURL,
+		MaxIdleConnsPerHost:   cfg.HTTPMaxIdlePerHost,
+		DisableKeepAlives:     cfg.HTTPDisableKeepAlives,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to configure HTTP transport: %w", err)
+	}
+
+	return &Bootstrap{
+		Config: cfg,
+		Client: client,
+		Logf:   logf,
+	}, nil
+}
+
+// Devices returns the account's device list, fetching it on first use.
+func (b *Bootstrap) Devices() ([]thinq.Device, error) {
+	if b.devices == nil {
+		devices, err := b.Client.GetDeviceList()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get device list: %w", err)
+		}
+		b.devices = devices
+	}
+	return b.devices, nil
+}
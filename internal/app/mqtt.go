@@ -0,0 +1,205 @@
+package app
+
+import (
+	"controle-arcondicionado/internal/config"
+	"controle-arcondicionado/internal/thinq"
+	"crypto/tls"
+	"crypto/x509"
+	_ "embed"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+)
+
+// amazonRootCA1 is the root CA that signs the AWS IoT broker certificates,
+// embedded so pinning works without any file on disk.
+//
+//go:embed amazon-root-ca1.pem
+var amazonRootCA1 []byte
+
+// ConnectMQTT resolves the broker route, obtains MQTT credentials,
+// connects and subscribes to every topic from the credentials, invoking
+// handler for each message. onConnect, when non-nil, runs after the topic
+// subscriptions succeed.
+func (b *Bootstrap) ConnectMQTT(handler mqtt.MessageHandler, onConnect func()) (mqtt.Client, *thinq.MQTTCredentials, error) {
+	b.Logf("Getting MQTT broker information...")
+	mqttServer, err := b.Client.GetMQTTRoute()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get MQTT route: %w", err)
+	}
+	b.Logf("MQTT Server: %s", mqttServer)
+
+	// Get MQTT credentials, reusing the on-disk cache when possible
+	b.Logf("Obtaining MQTT credentials...")
+	var credentials *thinq.MQTTCredentials
+	if b.Config.CredentialsFile != "off" {
+		manager := thinq.NewCredentialManager(b.Client, b.Config.CredentialsFile)
+		credentials, err = manager.Credentials()
+	} else {
+		credentials, err = b.Client.GetMQTTCredentials()
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get MQTT credentials: %w", err)
+	}
+	b.Logf("Received certificate, %d subscription and %d publication topic(s)",
+		len(credentials.Subscriptions), len(credentials.Publications))
+
+	// Setup TLS configuration
+	tlsConfig, err := createTLSConfig(b.Config, credentials, b.Logf)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create TLS config: %w", err)
+	}
+
+	opts := mqtt.NewClientOptions()
+	opts.AddBroker(fmt.Sprintf("ssl://%s", mqttServer))
+	opts.SetClientID(b.Config.ClientID)
+	opts.SetTLSConfig(tlsConfig)
+	opts.SetKeepAlive(60 * time.Second)
+	opts.SetPingTimeout(10 * time.Second)
+	opts.SetDefaultPublishHandler(handler)
+	opts.SetConnectionLostHandler(func(client mqtt.Client, err error) {
+		b.Logf("Connection lost: %v", err)
+	})
+	opts.SetOnConnectHandler(func(client mqtt.Client) {
+		b.Logf("Connected to MQTT broker!")
+
+		// Subscribe to all topics
+		for _, topic := range credentials.Subscriptions {
+			b.Logf("Subscribing to: %s", topic)
+			if token := client.Subscribe(topic, 1, nil); token.Wait() && token.Error() != nil {
+				b.Logf("Failed to subscribe to %s: %v", topic, token.Error())
+			}
+		}
+		if onConnect != nil {
+			onConnect()
+		}
+	})
+
+	mqttClient := mqtt.NewClient(opts)
+	if token := mqttClient.Connect(); token.Wait() && token.Error() != nil {
+		return nil, nil, fmt.Errorf("failed to connect to MQTT broker: %w", token.Error())
+	}
+
+	return mqttClient, credentials, nil
+}
+
+// DisconnectMQTT unsubscribes from all topics and disconnects cleanly.
+func (b *Bootstrap) DisconnectMQTT(mqttClient mqtt.Client, credentials *thinq.MQTTCredentials) {
+	b.Logf("Unsubscribing from topics...")
+	for _, topic := range credentials.Subscriptions {
+		if token := mqttClient.Unsubscribe(topic); token.Wait() && token.Error() != nil {
+			b.Logf("Failed to unsubscribe from %s: %v", topic, token.Error())
+		}
+	}
+
+	b.Logf("Disconnecting from MQTT broker...")
+	mqttClient.Disconnect(250)
+}
+
+// SubscribeDevices subscribes every device to events and push
+// notifications and prints a health summary. Devices whose subscriptions
+// failed keep being retried in the background with backoff, so a flaky
+// LG API does not leave them silently unmonitored. It returns the number
+// of devices that subscribed successfully on the first pass.
+func (b *Bootstrap) SubscribeDevices(devices []thinq.Device) int {
+	b.Logf("Subscribing to device events and push notifications...")
+	var failed []thinq.Device
+	for i, device := range devices {
+		b.Logf("[%d/%d] Subscribing to: %s", i+1, len(devices), device.Alias)
+		if err := b.subscribeDevice(device); err != nil {
+			b.Logf("Warning: %v", err)
+			failed = append(failed, device)
+		}
+	}
+
+	subscribed := len(devices) - len(failed)
+	if len(failed) == 0 {
+		b.Logf("Subscription summary: all %d device(s) subscribed", len(devices))
+		return subscribed
+	}
+
+	names := make([]string, len(failed))
+	for i, device := range failed {
+		names[i] = device.Alias
+	}
+	b.Logf("Subscription summary: %d/%d device(s) subscribed; retrying in the background: %s",
+		subscribed, len(devices), strings.Join(names, ", "))
+
+	for _, device := range failed {
+		go b.retrySubscribe(device)
+	}
+	return subscribed
+}
+
+// subscribeDevice subscribes one device to events and push notifications.
+func (b *Bootstrap) subscribeDevice(device thinq.Device) error {
+	if err := b.Client.SubscribeToDeviceEvents(device.DeviceID); err != nil {
+		return fmt.Errorf("failed to subscribe to events for %s: %w", device.Alias, err)
+	}
+	if err := b.Client.SubscribeToPushNotifications(device.DeviceID); err != nil {
+		return fmt.Errorf("failed to subscribe to push for %s: %w", device.Alias, err)
+	}
+	return nil
+}
+
+// retrySubscribe keeps retrying a failed device subscription with
+// exponential backoff until it succeeds.
+func (b *Bootstrap) retrySubscribe(device thinq.Device) {
+	delay := 30 * time.Second
+	for {
+		time.Sleep(delay)
+		err := b.subscribeDevice(device)
+		if err == nil {
+			b.Logf("Subscription recovered for %s", device.Alias)
+			return
+		}
+		if delay *= 2; delay > 10*time.Minute {
+			delay = 10 * time.Minute
+		}
+		b.Logf("Subscription retry for %s failed, next attempt in %s: %v", device.Alias, delay, err)
+	}
+}
+
+// createTLSConfig creates TLS configuration from credentials, applying the
+// optional root CA pinning and verification settings from the config.
+func createTLSConfig(cfg *config.Config, credentials *thinq.MQTTCredentials, logf func(format string, args ...interface{})) (*tls.Config, error) {
+	// Load client certificate
+	cert, err := tls.X509KeyPair([]byte(credentials.Certificate), []byte(credentials.PrivateKey))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load client certificate: %w", err)
+	}
+
+	tlsConfig := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ServerName:   cfg.MQTTServerName,
+	}
+
+	switch cfg.MQTTCAFile {
+	case "":
+		// System roots
+	case "embedded":
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(amazonRootCA1) {
+			return nil, fmt.Errorf("failed to parse embedded root CA")
+		}
+	default:
+		pemData, err := os.ReadFile(cfg.MQTTCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read root CA file: %w", err)
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(pemData) {
+			return nil, fmt.Errorf("no certificates found in root CA file %s", cfg.MQTTCAFile)
+		}
+	}
+
+	if cfg.MQTTInsecureSkipVerify {
+		logf("WARNING: MQTT broker certificate verification is disabled")
+		tlsConfig.InsecureSkipVerify = true
+	}
+
+	return tlsConfig, nil
+}
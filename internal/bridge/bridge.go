@@ -0,0 +1,200 @@
+// Package bridge republishes ThinQ events to a local MQTT broker so other
+// tools on the network can consume device state without LG credentials.
+package bridge
+
+import (
+	"controle-arcondicionado/internal/thinq"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+)
+
+// Options configures the connection to the local broker.
+type Options struct {
+	URL      string // broker URL (tcp://host:1883 or ssl://host:8883)
+	Username string
+	Password string
+	Prefix   string // topic prefix (default "thinq")
+	CAFile   string // root CA for ssl:// brokers (optional)
+}
+
+// state is the normalized device state published under <prefix>/<alias>/state.
+// Fields accumulate across events, since each event only carries changes.
+type state struct {
+	DeviceID           string   `json:"deviceId"`
+	Alias              string   `json:"alias"`
+	PowerOn            *bool    `json:"power,omitempty"`
+	JobMode            string   `json:"mode,omitempty"`
+	TargetTemperature  *float64 `json:"targetTemperature,omitempty"`
+	CurrentTemperature *float64 `json:"currentTemperature,omitempty"`
+	ErrorCode          string   `json:"error,omitempty"`
+	UpdatedAt          string   `json:"updatedAt"`
+}
+
+// Bridge forwards every ThinQ event to the local broker as a raw copy and
+// as a merged, normalized state document (retained, so late subscribers
+// see the last known state).
+type Bridge struct {
+	mu      sync.Mutex
+	client  mqtt.Client
+	prefix  string
+	aliases map[string]string
+	states  map[string]*state
+	logf    func(format string, args ...interface{})
+}
+
+// New connects to the local broker and returns a running bridge.
+func New(opts Options, devices []thinq.Device, logf func(format string, args ...interface{})) (*Bridge, error) {
+	if opts.Prefix == "" {
+		opts.Prefix = "thinq"
+	}
+
+	mqttOpts := mqtt.NewClientOptions()
+	mqttOpts.AddBroker(opts.URL)
+	mqttOpts.SetClientID("economizador-bridge")
+	mqttOpts.SetAutoReconnect(true)
+	mqttOpts.SetConnectRetryInterval(10 * time.Second)
+	if opts.Username != "" {
+		mqttOpts.SetUsername(opts.Username)
+		mqttOpts.SetPassword(opts.Password)
+	}
+	if strings.HasPrefix(opts.URL, "ssl://") || strings.HasPrefix(opts.URL, "tls://") {
+		tlsConfig := &tls.Config{}
+		if opts.CAFile != "" {
+			pemData, err := os.ReadFile(opts.CAFile)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read local broker CA file: %w", err)
+			}
+			tlsConfig.RootCAs = x509.NewCertPool()
+			if !tlsConfig.RootCAs.AppendCertsFromPEM(pemData) {
+				return nil, fmt.Errorf("no certificates found in local broker CA file %s", opts.CAFile)
+			}
+		}
+		mqttOpts.SetTLSConfig(tlsConfig)
+	}
+
+	aliases := make(map[string]string)
+	for _, device := range devices {
+		aliases[device.DeviceID] = device.Alias
+	}
+
+	bridge := &Bridge{
+		prefix:  opts.Prefix,
+		aliases: aliases,
+		states:  make(map[string]*state),
+		logf:    logf,
+	}
+	mqttOpts.SetOnConnectHandler(func(mqtt.Client) {
+		logf("Connected to local MQTT broker at %s", opts.URL)
+	})
+	mqttOpts.SetConnectionLostHandler(func(_ mqtt.Client, err error) {
+		logf("Local MQTT broker connection lost: %v", err)
+	})
+
+	bridge.client = mqtt.NewClient(mqttOpts)
+	if token := bridge.client.Connect(); token.Wait() && token.Error() != nil {
+		return nil, fmt.Errorf("failed to connect to local MQTT broker: %w", token.Error())
+	}
+
+	return bridge, nil
+}
+
+// Forward republishes a raw ThinQ payload and the updated normalized state.
+// Payloads that are not device status events — including ones that do not
+// parse at all — are forwarded raw only.
+func (b *Bridge) Forward(payload []byte) {
+	event, err := thinq.ParseEvent(payload)
+
+	// The raw copy goes out even for unparseable payloads, under an
+	// "unknown" segment when the device cannot be identified
+	segment := "unknown"
+	if err == nil {
+		segment = topicSegment(b.alias(event.DeviceID))
+	}
+	b.publish(fmt.Sprintf("%s/%s/raw", b.prefix, segment), payload, false)
+
+	if err != nil || event.PushType != "DEVICE_STATUS" {
+		return
+	}
+
+	alias := b.alias(event.DeviceID)
+	stateJSON, err := json.Marshal(b.merge(event, alias))
+	if err != nil {
+		b.logf("Failed to marshal bridge state: %v", err)
+		return
+	}
+	b.publish(fmt.Sprintf("%s/%s/state", b.prefix, topicSegment(alias)), stateJSON, true)
+}
+
+// merge folds an event into the cached state of its device.
+func (b *Bridge) merge(event *thinq.Event, alias string) state {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	current, ok := b.states[event.DeviceID]
+	if !ok {
+		current = &state{DeviceID: event.DeviceID, Alias: alias}
+		b.states[event.DeviceID] = current
+	}
+
+	if event.PowerOn != nil {
+		current.PowerOn = event.PowerOn
+	}
+	if event.JobMode != "" {
+		current.JobMode = event.JobMode
+	}
+	if event.TargetTemperature != nil {
+		current.TargetTemperature = event.TargetTemperature
+	}
+	if event.CurrentTemperature != nil {
+		current.CurrentTemperature = event.CurrentTemperature
+	}
+	if event.ErrorCode != "" {
+		if thinq.IsErrorClear(event.ErrorCode) {
+			current.ErrorCode = ""
+		} else {
+			current.ErrorCode = event.ErrorCode
+		}
+	}
+	current.UpdatedAt = time.Now().Format(time.RFC3339)
+
+	return *current
+}
+
+// publish sends a payload without blocking the ThinQ message handler.
+func (b *Bridge) publish(topic string, payload []byte, retained bool) {
+	token := b.client.Publish(topic, 0, retained, payload)
+	go func() {
+		if token.Wait() && token.Error() != nil {
+			b.logf("Failed to publish to %s: %v", topic, token.Error())
+		}
+	}()
+}
+
+// Close disconnects from the local broker.
+func (b *Bridge) Close() {
+	b.client.Disconnect(250)
+}
+
+// alias returns the friendly name of a device, falling back to its ID.
+func (b *Bridge) alias(deviceID string) string {
+	if alias := b.aliases[deviceID]; alias != "" {
+		return alias
+	}
+	return deviceID
+}
+
+// topicSegment makes an alias safe to use inside an MQTT topic.
+func topicSegment(alias string) string {
+	alias = strings.ReplaceAll(alias, "/", "-")
+	alias = strings.ReplaceAll(alias, "+", "-")
+	alias = strings.ReplaceAll(alias, "#", "-")
+	return strings.ReplaceAll(alias, " ", "_")
+}
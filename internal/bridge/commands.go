@@ -0,0 +1,149 @@
+package bridge
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+)
+
+// Controller is the subset of the ThinQ client the bridge uses to execute
+// commands received from the local broker.
+type Controller interface {
+	SetTemperature(deviceID string, temperature int) error
+	SetPower(deviceID string, on bool) error
+	SetJobMode(deviceID string, mode string) error
+	SetWindStrength(deviceID string, strength string) error
+}
+
+// command is the JSON accepted on <prefix>/<alias>/set. Omitted fields are
+// not changed.
+type command struct {
+	Power             *bool  `json:"power"`
+	Mode              string `json:"mode"`
+	TargetTemperature *int   `json:"targetTemperature"`
+	Fan               string `json:"fan"`
+}
+
+// Job modes and fan speeds the ThinQ air conditioner profile accepts.
+var (
+	validJobModes = map[string]bool{
+		"COOL": true, "HEAT": true, "AUTO": true, "FAN": true,
+		"AIR_DRY": true, "AIR_CLEAN": true, "JET": true,
+	}
+	validFanSpeeds = map[string]bool{
+		"SLOW": true, "LOW": true, "MID": true, "HIGH": true,
+		"POWER": true, "AUTO": true,
+	}
+)
+
+// Temperature range the air conditioner profile accepts, in °C.
+const (
+	minSettableTemperature = 16
+	maxSettableTemperature = 30
+)
+
+// ListenCommands subscribes to <prefix>/<alias>/set on the local broker and
+// translates incoming JSON commands into control calls. The outcome of each
+// command is published to <prefix>/<alias>/result.
+func (b *Bridge) ListenCommands(controller Controller) error {
+	topic := fmt.Sprintf("%s/+/set", b.prefix)
+	token := b.client.Subscribe(topic, 1, func(_ mqtt.Client, msg mqtt.Message) {
+		b.handleCommand(controller, msg)
+	})
+	if token.Wait() && token.Error() != nil {
+		return fmt.Errorf("failed to subscribe to %s: %w", topic, token.Error())
+	}
+	b.logf("Listening for commands on %s", topic)
+	return nil
+}
+
+// handleCommand executes one command message and publishes its result.
+func (b *Bridge) handleCommand(controller Controller, msg mqtt.Message) {
+	segment := commandTopicSegment(msg.Topic())
+	resultTopic := fmt.Sprintf("%s/%s/result", b.prefix, segment)
+
+	deviceID := b.deviceBySegment(segment)
+	if deviceID == "" {
+		b.publishResult(resultTopic, fmt.Errorf("unknown device %q", segment))
+		return
+	}
+
+	var cmd command
+	if err := json.Unmarshal(msg.Payload(), &cmd); err != nil {
+		b.publishResult(resultTopic, fmt.Errorf("invalid command JSON: %w", err))
+		return
+	}
+
+	b.publishResult(resultTopic, b.execute(controller, deviceID, cmd))
+}
+
+// execute validates and applies a command, stopping at the first failure.
+func (b *Bridge) execute(controller Controller, deviceID string, cmd command) error {
+	if cmd.Mode != "" && !validJobModes[strings.ToUpper(cmd.Mode)] {
+		return fmt.Errorf("invalid mode %q", cmd.Mode)
+	}
+	if cmd.Fan != "" && !validFanSpeeds[strings.ToUpper(cmd.Fan)] {
+		return fmt.Errorf("invalid fan speed %q", cmd.Fan)
+	}
+	if cmd.TargetTemperature != nil &&
+		(*cmd.TargetTemperature < minSettableTemperature || *cmd.TargetTemperature > maxSettableTemperature) {
+		return fmt.Errorf("temperature %d°C out of range (%d-%d)",
+			*cmd.TargetTemperature, minSettableTemperature, maxSettableTemperature)
+	}
+
+	if cmd.Power != nil {
+		if err := controller.SetPower(deviceID, *cmd.Power); err != nil {
+			return err
+		}
+	}
+	if cmd.Mode != "" {
+		if err := controller.SetJobMode(deviceID, strings.ToUpper(cmd.Mode)); err != nil {
+			return err
+		}
+	}
+	if cmd.TargetTemperature != nil {
+		if err := controller.SetTemperature(deviceID, *cmd.TargetTemperature); err != nil {
+			return err
+		}
+	}
+	if cmd.Fan != "" {
+		if err := controller.SetWindStrength(deviceID, strings.ToUpper(cmd.Fan)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// publishResult reports a command's outcome on its result topic.
+func (b *Bridge) publishResult(topic string, err error) {
+	result := map[string]interface{}{"ok": err == nil}
+	if err != nil {
+		result["error"] = err.Error()
+		b.logf("Local command failed: %v", err)
+	}
+	payload, _ := json.Marshal(result)
+	b.publish(topic, payload, false)
+}
+
+// deviceBySegment resolves a topic segment back to a device ID.
+func (b *Bridge) deviceBySegment(segment string) string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for deviceID, alias := range b.aliases {
+		if topicSegment(alias) == segment || deviceID == segment {
+			return deviceID
+		}
+	}
+	return ""
+}
+
+// commandTopicSegment extracts the alias segment from <prefix>/<alias>/set.
+func commandTopicSegment(topic string) string {
+	parts := strings.Split(topic, "/")
+	if len(parts) < 2 {
+		return topic
+	}
+	return parts[len(parts)-2]
+}
@@ -3,23 +3,195 @@ package config
 import (
 	"fmt"
 	"os"
+	"os/exec"
+	"path/filepath"
 	"regexp"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
 )
 
 type Config struct {
-	ThinQPAT       string
-	CountryCode    string
-	ClientID       string
-	MinTemperature int
+	ThinQPAT        string
+	CountryCode     string
+	ClientID        string
+	MinTemperature  int
+	MaxRunning      int            // maximum units running at once (0 = unlimited)
+	DryHumidity     int            // humidity % above which DRY mode is preferred (0 = off)
+	MinHumidity     int            // dehumidifier target humidity floor in % (0 = off)
+	AutoOffMinutes  int            // power a device off after this many minutes running (0 = off)
+	MaxDelta        int            // max °C the target may sit below the room temperature (0 = off)
+	EnergySaving    bool           // force the native powerSave switch on while a unit runs
+	EscalateAfter   int            // corrections/hour before escalating (0 = off)
+	EscalateLock    bool           // switch escalated devices to notify mode
+	MinSubscribed   int            // fail startup when fewer devices subscribe (0 = off)
+	ControlPath     string         // preferred control transport: "rest" (default) or "mqtt"
+	AnomalyMinutes  int            // "room not cooling" alert window in minutes (0 = off)
+	AnomalyMinDrop  float64        // expected °C drop over the anomaly window
+	APIListen       string         // local REST API address ("off" to disable)
+	GRPCListen      string         // gRPC API address ("" = disabled)
+	NotifyWebhook   string         // webhook URL for notifications (optional)
+	HistoryFile     string         // activity history NDJSON path ("off" to disable)
+	HistoryDays     int            // days of raw history kept before hourly rollup (0 = keep forever)
+	ReportTime      string         // time of day (HH:MM) for the daily report
+	CredentialsFile string         // MQTT credential cache path ("off" to disable)
+	StateFile       string         // enforcement state snapshot path ("off" to disable)
+	Location        *time.Location // timezone for schedules (TIMEZONE, default system local)
+
+	HTTPProxyURL          string        // explicit proxy for the ThinQ API (HTTP(S)_PROXY env also honored)
+	HTTPTimeout           time.Duration // overall request timeout (0 = keep the 30s default)
+	HTTPDialTimeout       time.Duration // TCP connect timeout (0 = 10s default)
+	HTTPTLSTimeout        time.Duration // TLS handshake timeout (0 = 10s default)
+	HTTPHeaderTimeout     time.Duration // response-header timeout (0 = none)
+	HTTPMaxIdlePerHost    int           // pooled connections per host (0 = Go default)
+	HTTPDisableKeepAlives bool          // open a fresh connection per request
+
+	MQTTCAFile             string // broker root CA: "" = system roots, "embedded" = Amazon Root CA 1, otherwise a PEM path
+	MQTTServerName         string // expected TLS server name (defaults to the broker host)
+	MQTTInsecureSkipVerify bool   // skip broker certificate verification (debugging only)
+
+	LocalMQTTURL      string // local broker for the republish bridge ("" = disabled)
+	LocalMQTTUsername string
+	LocalMQTTPassword string
+	LocalMQTTPrefix   string // topic prefix on the local broker (default "thinq")
+	LocalMQTTCAFile   string // root CA for ssl:// local brokers (optional)
+
+	// Optional structured configuration loaded from the YAML config file
+	Zones           []Zone
+	DeviceOverrides []DeviceOverride
+	Presence        *PresenceConfig
+	Scenes          map[string]Scene
+}
+
+// Scene is a named preset of device settings ("dormir", "sair", ...).
+type Scene struct {
+	RelaxMinutes int         `yaml:"relax_minutes"` // suspend enforcement on touched devices (0 = off)
+	Devices      []SceneStep `yaml:"devices"`
+}
+
+// SceneStep holds the settings a scene applies to one device or zone.
+// Omitted fields are left untouched.
+type SceneStep struct {
+	Name        string `yaml:"name"`        // device alias, device ID or zone name
+	Power       *bool  `yaml:"power"`       // true = on, false = off
+	Mode        string `yaml:"mode"`        // job mode (COOL, FAN, AUTO, ...)
+	Temperature *int   `yaml:"temperature"` // target temperature in °C
+	Fan         string `yaml:"fan"`         // fan speed (LOW, MID, HIGH, AUTO)
+}
+
+// Policy holds the tunable limits that can be attached to a zone or a
+// single device. Nil fields mean "inherit from the level above".
+type Policy struct {
+	MinTemperature *int   `yaml:"min_temperature"`
+	MaxRunning     *int   `yaml:"max_running"`
+	Mode           string `yaml:"mode"`             // enforce (default), notify or log
+	RampMinutes    *int   `yaml:"ramp_minutes"`     // raise 1°C per step instead of clamping (0 = off)
+	DryHumidity    *int   `yaml:"dry_humidity"`     // switch to DRY above this humidity % (0 = off)
+	MinHumidity    *int   `yaml:"min_humidity"`     // dehumidifier target humidity floor in % (0 = off)
+	AutoOffMinutes *int   `yaml:"auto_off_minutes"` // power off after this many minutes running (0 = off)
+	MaxDelta       *int   `yaml:"max_delta"`        // max °C below the room temperature (0 = off)
+	Action         string `yaml:"action"`           // corrective action: adjust (default), fan or power_off
+	EnergySaving   *bool  `yaml:"energy_saving"`    // force the native powerSave switch while running
+}
+
+// Zone groups devices (by alias) under a shared policy.
+type Zone struct {
+	Name    string   `yaml:"name"`
+	Devices []string `yaml:"devices"`
+	Policy  Policy   `yaml:"policy"`
+}
+
+// DeviceOverride attaches a policy to a single device by alias,
+// taking precedence over its zone's policy.
+type DeviceOverride struct {
+	Name   string `yaml:"name"`
+	Policy Policy `yaml:"policy"`
+}
+
+// PresenceConfig enables presence-aware enforcement.
+type PresenceConfig struct {
+	PollSeconds   int                  `yaml:"poll_interval"` // seconds between checks (default 60)
+	HomeAssistant *HomeAssistantConfig `yaml:"home_assistant"`
+	PingHosts     []string             `yaml:"ping_hosts"`
+	Away          AwayConfig           `yaml:"away"`
+}
+
+// HomeAssistantConfig points at a Home Assistant instance and the person
+// entities that count as "home".
+type HomeAssistantConfig struct {
+	URL      string   `yaml:"url"`
+	Token    string   `yaml:"token"`
+	Entities []string `yaml:"entities"`
+}
+
+// AwayConfig is the policy applied while nobody is home.
+type AwayConfig struct {
+	MinTemperature int  `yaml:"min_temperature"` // raised minimum (0 = unchanged)
+	PowerOff       bool `yaml:"power_off"`       // power everything off
+}
+
+// fileConfig is the shape of the optional YAML config file.
+type fileConfig struct {
+	Zones    []Zone           `yaml:"zones"`
+	Devices  []DeviceOverride `yaml:"devices"`
+	Presence *PresenceConfig  `yaml:"presence"`
+	Scenes   map[string]Scene `yaml:"scenes"`
+}
+
+// DefaultConfigFile is used when CONFIG_FILE is not set.
+const DefaultConfigFile = "config.yaml"
+
+// resolvePAT resolves the ThinQ Personal Access Token from one of the
+// supported secret sources, in order of precedence: THINQ_PAT_FILE (a
+// mounted secret, e.g. /run/secrets/thinq_pat), THINQ_PAT_CMD (a command
+// printing the token, e.g. `pass show lg/pat`) and finally THINQ_PAT
+// itself. The value is never logged.
+func resolvePAT() (string, error) {
+	if path := os.Getenv("THINQ_PAT_FILE"); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("error reading THINQ_PAT_FILE: %w", err)
+		}
+		return strings.TrimSpace(string(data)), nil
+	}
+
+	if command := os.Getenv("THINQ_PAT_CMD"); command != "" {
+		out, err := exec.Command("sh", "-c", command).Output()
+		if err != nil {
+			return "", fmt.Errorf("error running THINQ_PAT_CMD: %w", err)
+		}
+		return strings.TrimSpace(string(out)), nil
+	}
+
+	return os.Getenv("THINQ_PAT"), nil
+}
+
+// userPath returns the path of name inside the per-user config directory
+// (~/.config/economizador on Linux, %AppData%\economizador on Windows).
+func userPath(name string) (string, bool) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", false
+	}
+	return filepath.Join(dir, "economizador", name), true
 }
 
 func Load() (*Config, error) {
+	// Load the .env from the working directory; fall back to the per-user
+	// config directory (e.g. %AppData%\economizador on Windows, where the
+	// working directory of a service is not useful)
 	if err := godotenv.Load(); err != nil {
-		return nil, fmt.Errorf("error loading .env file: %w", err)
+		fallback, ok := userPath(".env")
+		if !ok {
+			return nil, fmt.Errorf("error loading .env file: %w", err)
+		}
+		if err := godotenv.Load(fallback); err != nil {
+			return nil, fmt.Errorf("error loading .env file (also tried %s): %w", fallback, err)
+		}
 	}
 
 	minTemp := 21 // Default minimum temperature
@@ -32,13 +204,201 @@ func Load() (*Config, error) {
 		minTemp = 21
 	}
 
+	maxRunning := 0 // Unlimited by default
+	if maxStr := os.Getenv("MAX_RUNNING"); maxStr != "" {
+		if max, err := strconv.Atoi(maxStr); err == nil && max > 0 {
+			maxRunning = max
+		}
+	}
+
+	dryHumidity := 0 // Disabled by default
+	if dryStr := os.Getenv("DRY_HUMIDITY"); dryStr != "" {
+		if dry, err := strconv.Atoi(dryStr); err == nil && dry > 0 {
+			dryHumidity = dry
+		}
+	}
+
+	minHumidity := 0 // Disabled by default
+	if humidityStr := os.Getenv("MIN_HUMIDITY"); humidityStr != "" {
+		if humidity, err := strconv.Atoi(humidityStr); err == nil && humidity > 0 {
+			minHumidity = humidity
+		}
+	}
+
+	autoOffMinutes := 0 // Disabled by default
+	if autoOffStr := os.Getenv("AUTO_OFF_MINUTES"); autoOffStr != "" {
+		if minutes, err := strconv.Atoi(autoOffStr); err == nil && minutes > 0 {
+			autoOffMinutes = minutes
+		}
+	}
+
+	maxDelta := 0 // Disabled by default
+	if deltaStr := os.Getenv("MAX_DELTA"); deltaStr != "" {
+		if delta, err := strconv.Atoi(deltaStr); err == nil && delta > 0 {
+			maxDelta = delta
+		}
+	}
+
+	energySaving := false // Disabled by default
+	if saveStr := os.Getenv("ENERGY_SAVING"); saveStr != "" {
+		if save, err := strconv.ParseBool(saveStr); err == nil {
+			energySaving = save
+		}
+	}
+
+	escalateAfter := 0 // Disabled by default
+	if escalateStr := os.Getenv("ESCALATE_AFTER"); escalateStr != "" {
+		if after, err := strconv.Atoi(escalateStr); err == nil && after > 0 {
+			escalateAfter = after
+		}
+	}
+
+	escalateLock := false // Disabled by default
+	if lockStr := os.Getenv("ESCALATE_LOCK"); lockStr != "" {
+		if lock, err := strconv.ParseBool(lockStr); err == nil {
+			escalateLock = lock
+		}
+	}
+
+	minSubscribed := 0 // Disabled by default
+	if subStr := os.Getenv("MIN_SUBSCRIBED"); subStr != "" {
+		if sub, err := strconv.Atoi(subStr); err == nil && sub > 0 {
+			minSubscribed = sub
+		}
+	}
+
+	anomalyMinutes := 30 // "room not cooling" alert window (0 = off)
+	if anomalyStr := os.Getenv("ANOMALY_MINUTES"); anomalyStr != "" {
+		if minutes, err := strconv.Atoi(anomalyStr); err == nil && minutes >= 0 {
+			anomalyMinutes = minutes
+		}
+	}
+
+	anomalyMinDrop := 0.5 // expected °C drop over the window
+	if dropStr := os.Getenv("ANOMALY_MIN_DROP"); dropStr != "" {
+		if drop, err := strconv.ParseFloat(dropStr, 64); err == nil && drop > 0 {
+			anomalyMinDrop = drop
+		}
+	}
+
+	pat, err := resolvePAT()
+	if err != nil {
+		return nil, err
+	}
+
 	cfg := &Config{
-		ThinQPAT:       os.Getenv("THINQ_PAT"),
+		ThinQPAT:       pat,
 		CountryCode:    os.Getenv("THINQ_COUNTRY_CODE"),
 		ClientID:       os.Getenv("THINQ_CLIENT_ID"),
 		MinTemperature: minTemp,
+		MaxRunning:     maxRunning,
+		DryHumidity:    dryHumidity,
+		MinHumidity:    minHumidity,
+		AutoOffMinutes: autoOffMinutes,
+		MaxDelta:       maxDelta,
+		EnergySaving:   energySaving,
+		EscalateAfter:  escalateAfter,
+		EscalateLock:   escalateLock,
+		MinSubscribed:  minSubscribed,
+		AnomalyMinutes: anomalyMinutes,
+		AnomalyMinDrop: anomalyMinDrop,
+		APIListen:      os.Getenv("API_LISTEN"),
+		NotifyWebhook:  os.Getenv("NOTIFY_WEBHOOK_URL"),
+	}
+
+	if cfg.APIListen == "" {
+		cfg.APIListen = "127.0.0.1:8765" // Local API enabled by default
+	}
+
+	cfg.GRPCListen = os.Getenv("GRPC_LISTEN")
+	if cfg.GRPCListen == "off" {
+		cfg.GRPCListen = ""
+	}
+
+	cfg.HistoryFile = os.Getenv("HISTORY_FILE")
+	if cfg.HistoryFile == "" {
+		cfg.HistoryFile = "history.ndjson"
+	}
+
+	cfg.HistoryDays = 30
+	if daysStr := os.Getenv("HISTORY_RETENTION_DAYS"); daysStr != "" {
+		if days, err := strconv.Atoi(daysStr); err == nil && days >= 0 {
+			cfg.HistoryDays = days
+		}
+	}
+
+	cfg.ReportTime = os.Getenv("REPORT_TIME")
+	if cfg.ReportTime == "" {
+		cfg.ReportTime = "23:50"
+	}
+
+	cfg.CredentialsFile = os.Getenv("MQTT_CREDENTIALS_FILE")
+	if cfg.CredentialsFile == "" {
+		cfg.CredentialsFile = "mqtt-credentials.json"
+	}
+
+	cfg.StateFile = os.Getenv("STATE_FILE")
+	if cfg.StateFile == "" {
+		cfg.StateFile = "state.json"
+	}
+
+	cfg.ControlPath = os.Getenv("CONTROL_PATH")
+	switch cfg.ControlPath {
+	case "":
+		cfg.ControlPath = "rest"
+	case "rest", "mqtt":
+	default:
+		return nil, fmt.Errorf("invalid CONTROL_PATH %q (expected rest or mqtt)", cfg.ControlPath)
+	}
+
+	// HTTP transport tuning for restrictive networks; Go durations ("45s")
+	envDuration := func(name string) time.Duration {
+		if str := os.Getenv(name); str != "" {
+			if duration, err := time.ParseDuration(str); err == nil && duration > 0 {
+				return duration
+			}
+		}
+		return 0
+	}
+	cfg.HTTPProxyURL = os.Getenv("HTTP_PROXY_URL")
+	cfg.HTTPTimeout = envDuration("HTTP_TIMEOUT")
+	cfg.HTTPDialTimeout = envDuration("HTTP_DIAL_TIMEOUT")
+	cfg.HTTPTLSTimeout = envDuration("HTTP_TLS_TIMEOUT")
+	cfg.HTTPHeaderTimeout = envDuration("HTTP_HEADER_TIMEOUT")
+	if idleStr := os.Getenv("HTTP_MAX_IDLE_PER_HOST"); idleStr != "" {
+		if idle, err := strconv.Atoi(idleStr); err == nil && idle > 0 {
+			cfg.HTTPMaxIdlePerHost = idle
+		}
+	}
+	if keepStr := os.Getenv("HTTP_DISABLE_KEEPALIVES"); keepStr != "" {
+		if disable, err := strconv.ParseBool(keepStr); err == nil {
+			cfg.HTTPDisableKeepAlives = disable
+		}
 	}
 
+	cfg.MQTTCAFile = os.Getenv("MQTT_CA_FILE")
+	cfg.MQTTServerName = os.Getenv("MQTT_SERVER_NAME")
+	if skipStr := os.Getenv("MQTT_INSECURE_SKIP_VERIFY"); skipStr != "" {
+		if skip, err := strconv.ParseBool(skipStr); err == nil {
+			cfg.MQTTInsecureSkipVerify = skip
+		}
+	}
+
+	cfg.Location = time.Local
+	if tz := os.Getenv("TIMEZONE"); tz != "" {
+		loc, err := time.LoadLocation(tz)
+		if err != nil {
+			return nil, fmt.Errorf("invalid TIMEZONE %q: %w", tz, err)
+		}
+		cfg.Location = loc
+	}
+
+	cfg.LocalMQTTURL = os.Getenv("LOCAL_MQTT_URL")
+	cfg.LocalMQTTUsername = os.Getenv("LOCAL_MQTT_USERNAME")
+	cfg.LocalMQTTPassword = os.Getenv("LOCAL_MQTT_PASSWORD")
+	cfg.LocalMQTTPrefix = os.Getenv("LOCAL_MQTT_PREFIX")
+	cfg.LocalMQTTCAFile = os.Getenv("LOCAL_MQTT_CA_FILE")
+
 	if cfg.ThinQPAT == "" {
 		return nil, fmt.Errorf("THINQ_PAT is required")
 	}
@@ -52,9 +412,53 @@ func Load() (*Config, error) {
 		cfg.ClientID = generateClientID()
 	}
 
+	if err := cfg.loadFile(); err != nil {
+		return nil, err
+	}
+
 	return cfg, nil
 }
 
+// loadFile reads the optional YAML config file with zones and per-device
+// overrides. A missing file is not an error unless CONFIG_FILE was set
+// explicitly.
+func (c *Config) loadFile() error {
+	path := os.Getenv("CONFIG_FILE")
+	explicit := path != ""
+	if path == "" {
+		path = DefaultConfigFile
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil && os.IsNotExist(err) && !explicit {
+		// Also check the per-user config directory before giving up
+		if fallback, ok := userPath(DefaultConfigFile); ok {
+			path = fallback
+			data, err = os.ReadFile(path)
+		}
+	}
+	if err != nil {
+		if os.IsNotExist(err) && !explicit {
+			return nil
+		}
+		return fmt.Errorf("error loading config file %s: %w", path, err)
+	}
+
+	var fc fileConfig
+	if err := yaml.Unmarshal(data, &fc); err != nil {
+		return fmt.Errorf("error parsing config file %s: %w", path, err)
+	}
+
+	c.Zones = fc.Zones
+	c.DeviceOverrides = fc.Devices
+	c.Presence = fc.Presence
+	c.Scenes = fc.Scenes
+	if c.Presence != nil && c.Presence.PollSeconds <= 0 {
+		c.Presence.PollSeconds = 60
+	}
+	return nil
+}
+
 func generateClientID() string {
 	// AWS IoT Thing names must match pattern: [a-zA-Z0-9:_-]+
 	// Generate UUID and format it properly
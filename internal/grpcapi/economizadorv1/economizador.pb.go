@@ -0,0 +1,658 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.12
+// 	protoc        (unknown)
+// source: economizador/v1/economizador.proto
+
+package economizadorv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ListDevicesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDevicesRequest) Reset() {
+	*x = ListDevicesRequest{}
+	mi := &file_economizador_v1_economizador_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDevicesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDevicesRequest) ProtoMessage() {}
+
+func (x *ListDevicesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_economizador_v1_economizador_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDevicesRequest.ProtoReflect.Descriptor instead.
+func (*ListDevicesRequest) Descriptor() ([]byte, []int) {
+	return file_economizador_v1_economizador_proto_rawDescGZIP(), []int{0}
+}
+
+type Device struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	DeviceId       string                 `protobuf:"bytes,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	Alias          string                 `protobuf:"bytes,2,opt,name=alias,proto3" json:"alias,omitempty"`
+	Zone           string                 `protobuf:"bytes,3,opt,name=zone,proto3" json:"zone,omitempty"`
+	MinTemperature int32                  `protobuf:"varint,4,opt,name=min_temperature,json=minTemperature,proto3" json:"min_temperature,omitempty"`
+	Mode           string                 `protobuf:"bytes,5,opt,name=mode,proto3" json:"mode,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Device) Reset() {
+	*x = Device{}
+	mi := &file_economizador_v1_economizador_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Device) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Device) ProtoMessage() {}
+
+func (x *Device) ProtoReflect() protoreflect.Message {
+	mi := &file_economizador_v1_economizador_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Device.ProtoReflect.Descriptor instead.
+func (*Device) Descriptor() ([]byte, []int) {
+	return file_economizador_v1_economizador_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Device) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+func (x *Device) GetAlias() string {
+	if x != nil {
+		return x.Alias
+	}
+	return ""
+}
+
+func (x *Device) GetZone() string {
+	if x != nil {
+		return x.Zone
+	}
+	return ""
+}
+
+func (x *Device) GetMinTemperature() int32 {
+	if x != nil {
+		return x.MinTemperature
+	}
+	return 0
+}
+
+func (x *Device) GetMode() string {
+	if x != nil {
+		return x.Mode
+	}
+	return ""
+}
+
+type ListDevicesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Devices       []*Device              `protobuf:"bytes,1,rep,name=devices,proto3" json:"devices,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDevicesResponse) Reset() {
+	*x = ListDevicesResponse{}
+	mi := &file_economizador_v1_economizador_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDevicesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDevicesResponse) ProtoMessage() {}
+
+func (x *ListDevicesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_economizador_v1_economizador_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDevicesResponse.ProtoReflect.Descriptor instead.
+func (*ListDevicesResponse) Descriptor() ([]byte, []int) {
+	return file_economizador_v1_economizador_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListDevicesResponse) GetDevices() []*Device {
+	if x != nil {
+		return x.Devices
+	}
+	return nil
+}
+
+// GetStatusRequest identifies a device by ID or alias (case-insensitive).
+type GetStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Device        string                 `protobuf:"bytes,1,opt,name=device,proto3" json:"device,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStatusRequest) Reset() {
+	*x = GetStatusRequest{}
+	mi := &file_economizador_v1_economizador_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStatusRequest) ProtoMessage() {}
+
+func (x *GetStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_economizador_v1_economizador_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStatusRequest.ProtoReflect.Descriptor instead.
+func (*GetStatusRequest) Descriptor() ([]byte, []int) {
+	return file_economizador_v1_economizador_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetStatusRequest) GetDevice() string {
+	if x != nil {
+		return x.Device
+	}
+	return ""
+}
+
+type DeviceStatus struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	DeviceId           string                 `protobuf:"bytes,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	Alias              string                 `protobuf:"bytes,2,opt,name=alias,proto3" json:"alias,omitempty"`
+	PowerOn            *bool                  `protobuf:"varint,3,opt,name=power_on,json=powerOn,proto3,oneof" json:"power_on,omitempty"`
+	JobMode            string                 `protobuf:"bytes,4,opt,name=job_mode,json=jobMode,proto3" json:"job_mode,omitempty"`
+	TargetTemperature  *float64               `protobuf:"fixed64,5,opt,name=target_temperature,json=targetTemperature,proto3,oneof" json:"target_temperature,omitempty"`
+	CurrentTemperature *float64               `protobuf:"fixed64,6,opt,name=current_temperature,json=currentTemperature,proto3,oneof" json:"current_temperature,omitempty"`
+	ErrorCode          string                 `protobuf:"bytes,7,opt,name=error_code,json=errorCode,proto3" json:"error_code,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *DeviceStatus) Reset() {
+	*x = DeviceStatus{}
+	mi := &file_economizador_v1_economizador_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeviceStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeviceStatus) ProtoMessage() {}
+
+func (x *DeviceStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_economizador_v1_economizador_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeviceStatus.ProtoReflect.Descriptor instead.
+func (*DeviceStatus) Descriptor() ([]byte, []int) {
+	return file_economizador_v1_economizador_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *DeviceStatus) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+func (x *DeviceStatus) GetAlias() string {
+	if x != nil {
+		return x.Alias
+	}
+	return ""
+}
+
+func (x *DeviceStatus) GetPowerOn() bool {
+	if x != nil && x.PowerOn != nil {
+		return *x.PowerOn
+	}
+	return false
+}
+
+func (x *DeviceStatus) GetJobMode() string {
+	if x != nil {
+		return x.JobMode
+	}
+	return ""
+}
+
+func (x *DeviceStatus) GetTargetTemperature() float64 {
+	if x != nil && x.TargetTemperature != nil {
+		return *x.TargetTemperature
+	}
+	return 0
+}
+
+func (x *DeviceStatus) GetCurrentTemperature() float64 {
+	if x != nil && x.CurrentTemperature != nil {
+		return *x.CurrentTemperature
+	}
+	return 0
+}
+
+func (x *DeviceStatus) GetErrorCode() string {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return ""
+}
+
+type SetTemperatureRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Device        string                 `protobuf:"bytes,1,opt,name=device,proto3" json:"device,omitempty"`
+	Temperature   int32                  `protobuf:"varint,2,opt,name=temperature,proto3" json:"temperature,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetTemperatureRequest) Reset() {
+	*x = SetTemperatureRequest{}
+	mi := &file_economizador_v1_economizador_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetTemperatureRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetTemperatureRequest) ProtoMessage() {}
+
+func (x *SetTemperatureRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_economizador_v1_economizador_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetTemperatureRequest.ProtoReflect.Descriptor instead.
+func (*SetTemperatureRequest) Descriptor() ([]byte, []int) {
+	return file_economizador_v1_economizador_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *SetTemperatureRequest) GetDevice() string {
+	if x != nil {
+		return x.Device
+	}
+	return ""
+}
+
+func (x *SetTemperatureRequest) GetTemperature() int32 {
+	if x != nil {
+		return x.Temperature
+	}
+	return 0
+}
+
+type SetTemperatureResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetTemperatureResponse) Reset() {
+	*x = SetTemperatureResponse{}
+	mi := &file_economizador_v1_economizador_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetTemperatureResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetTemperatureResponse) ProtoMessage() {}
+
+func (x *SetTemperatureResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_economizador_v1_economizador_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetTemperatureResponse.ProtoReflect.Descriptor instead.
+func (*SetTemperatureResponse) Descriptor() ([]byte, []int) {
+	return file_economizador_v1_economizador_proto_rawDescGZIP(), []int{6}
+}
+
+type StreamEventsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamEventsRequest) Reset() {
+	*x = StreamEventsRequest{}
+	mi := &file_economizador_v1_economizador_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamEventsRequest) ProtoMessage() {}
+
+func (x *StreamEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_economizador_v1_economizador_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamEventsRequest.ProtoReflect.Descriptor instead.
+func (*StreamEventsRequest) Descriptor() ([]byte, []int) {
+	return file_economizador_v1_economizador_proto_rawDescGZIP(), []int{7}
+}
+
+type DeviceEvent struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	DeviceId           string                 `protobuf:"bytes,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	Alias              string                 `protobuf:"bytes,2,opt,name=alias,proto3" json:"alias,omitempty"`
+	PushType           string                 `protobuf:"bytes,3,opt,name=push_type,json=pushType,proto3" json:"push_type,omitempty"`
+	TargetTemperature  *float64               `protobuf:"fixed64,4,opt,name=target_temperature,json=targetTemperature,proto3,oneof" json:"target_temperature,omitempty"`
+	CurrentTemperature *float64               `protobuf:"fixed64,5,opt,name=current_temperature,json=currentTemperature,proto3,oneof" json:"current_temperature,omitempty"`
+	JobMode            string                 `protobuf:"bytes,6,opt,name=job_mode,json=jobMode,proto3" json:"job_mode,omitempty"`
+	PowerOn            *bool                  `protobuf:"varint,7,opt,name=power_on,json=powerOn,proto3,oneof" json:"power_on,omitempty"`
+	ErrorCode          string                 `protobuf:"bytes,8,opt,name=error_code,json=errorCode,proto3" json:"error_code,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *DeviceEvent) Reset() {
+	*x = DeviceEvent{}
+	mi := &file_economizador_v1_economizador_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeviceEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeviceEvent) ProtoMessage() {}
+
+func (x *DeviceEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_economizador_v1_economizador_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeviceEvent.ProtoReflect.Descriptor instead.
+func (*DeviceEvent) Descriptor() ([]byte, []int) {
+	return file_economizador_v1_economizador_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *DeviceEvent) GetDeviceId() string {
+	if x != nil {
+		return x.DeviceId
+	}
+	return ""
+}
+
+func (x *DeviceEvent) GetAlias() string {
+	if x != nil {
+		return x.Alias
+	}
+	return ""
+}
+
+func (x *DeviceEvent) GetPushType() string {
+	if x != nil {
+		return x.PushType
+	}
+	return ""
+}
+
+func (x *DeviceEvent) GetTargetTemperature() float64 {
+	if x != nil && x.TargetTemperature != nil {
+		return *x.TargetTemperature
+	}
+	return 0
+}
+
+func (x *DeviceEvent) GetCurrentTemperature() float64 {
+	if x != nil && x.CurrentTemperature != nil {
+		return *x.CurrentTemperature
+	}
+	return 0
+}
+
+func (x *DeviceEvent) GetJobMode() string {
+	if x != nil {
+		return x.JobMode
+	}
+	return ""
+}
+
+func (x *DeviceEvent) GetPowerOn() bool {
+	if x != nil && x.PowerOn != nil {
+		return *x.PowerOn
+	}
+	return false
+}
+
+func (x *DeviceEvent) GetErrorCode() string {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return ""
+}
+
+var File_economizador_v1_economizador_proto protoreflect.FileDescriptor
+
+const file_economizador_v1_economizador_proto_rawDesc = "" +
+	"\n" +
+	"\"economizador/v1/economizador.proto\x12\x0feconomizador.v1\"\x14\n" +
+	"\x12ListDevicesRequest\"\x8c\x01\n" +
+	"\x06Device\x12\x1b\n" +
+	"\tdevice_id\x18\x01 \x01(\tR\bdeviceId\x12\x14\n" +
+	"\x05alias\x18\x02 \x01(\tR\x05alias\x12\x12\n" +
+	"\x04zone\x18\x03 \x01(\tR\x04zone\x12'\n" +
+	"\x0fmin_temperature\x18\x04 \x01(\x05R\x0eminTemperature\x12\x12\n" +
+	"\x04mode\x18\x05 \x01(\tR\x04mode\"H\n" +
+	"\x13ListDevicesResponse\x121\n" +
+	"\adevices\x18\x01 \x03(\v2\x17.economizador.v1.DeviceR\adevices\"*\n" +
+	"\x10GetStatusRequest\x12\x16\n" +
+	"\x06device\x18\x01 \x01(\tR\x06device\"\xc1\x02\n" +
+	"\fDeviceStatus\x12\x1b\n" +
+	"\tdevice_id\x18\x01 \x01(\tR\bdeviceId\x12\x14\n" +
+	"\x05alias\x18\x02 \x01(\tR\x05alias\x12\x1e\n" +
+	"\bpower_on\x18\x03 \x01(\bH\x00R\apowerOn\x88\x01\x01\x12\x19\n" +
+	"\bjob_mode\x18\x04 \x01(\tR\ajobMode\x122\n" +
+	"\x12target_temperature\x18\x05 \x01(\x01H\x01R\x11targetTemperature\x88\x01\x01\x124\n" +
+	"\x13current_temperature\x18\x06 \x01(\x01H\x02R\x12currentTemperature\x88\x01\x01\x12\x1d\n" +
+	"\n" +
+	"error_code\x18\a \x01(\tR\terrorCodeB\v\n" +
+	"\t_power_onB\x15\n" +
+	"\x13_target_temperatureB\x16\n" +
+	"\x14_current_temperature\"Q\n" +
+	"\x15SetTemperatureRequest\x12\x16\n" +
+	"\x06device\x18\x01 \x01(\tR\x06device\x12 \n" +
+	"\vtemperature\x18\x02 \x01(\x05R\vtemperature\"\x18\n" +
+	"\x16SetTemperatureResponse\"\x15\n" +
+	"\x13StreamEventsRequest\"\xdd\x02\n" +
+	"\vDeviceEvent\x12\x1b\n" +
+	"\tdevice_id\x18\x01 \x01(\tR\bdeviceId\x12\x14\n" +
+	"\x05alias\x18\x02 \x01(\tR\x05alias\x12\x1b\n" +
+	"\tpush_type\x18\x03 \x01(\tR\bpushType\x122\n" +
+	"\x12target_temperature\x18\x04 \x01(\x01H\x00R\x11targetTemperature\x88\x01\x01\x124\n" +
+	"\x13current_temperature\x18\x05 \x01(\x01H\x01R\x12currentTemperature\x88\x01\x01\x12\x19\n" +
+	"\bjob_mode\x18\x06 \x01(\tR\ajobMode\x12\x1e\n" +
+	"\bpower_on\x18\a \x01(\bH\x02R\apowerOn\x88\x01\x01\x12\x1d\n" +
+	"\n" +
+	"error_code\x18\b \x01(\tR\terrorCodeB\x15\n" +
+	"\x13_target_temperatureB\x16\n" +
+	"\x14_current_temperatureB\v\n" +
+	"\t_power_on2\xf7\x02\n" +
+	"\x13EconomizadorService\x12X\n" +
+	"\vListDevices\x12#.economizador.v1.ListDevicesRequest\x1a$.economizador.v1.ListDevicesResponse\x12M\n" +
+	"\tGetStatus\x12!.economizador.v1.GetStatusRequest\x1a\x1d.economizador.v1.DeviceStatus\x12a\n" +
+	"\x0eSetTemperature\x12&.economizador.v1.SetTemperatureRequest\x1a'.economizador.v1.SetTemperatureResponse\x12T\n" +
+	"\fStreamEvents\x12$.economizador.v1.StreamEventsRequest\x1a\x1c.economizador.v1.DeviceEvent0\x01B9Z7controle-arcondicionado/internal/grpcapi/economizadorv1b\x06proto3"
+
+var (
+	file_economizador_v1_economizador_proto_rawDescOnce sync.Once
+	file_economizador_v1_economizador_proto_rawDescData []byte
+)
+
+func file_economizador_v1_economizador_proto_rawDescGZIP() []byte {
+	file_economizador_v1_economizador_proto_rawDescOnce.Do(func() {
+		file_economizador_v1_economizador_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_economizador_v1_economizador_proto_rawDesc), len(file_economizador_v1_economizador_proto_rawDesc)))
+	})
+	return file_economizador_v1_economizador_proto_rawDescData
+}
+
+var file_economizador_v1_economizador_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_economizador_v1_economizador_proto_goTypes = []any{
+	(*ListDevicesRequest)(nil),     // 0: economizador.v1.ListDevicesRequest
+	(*Device)(nil),                 // 1: economizador.v1.Device
+	(*ListDevicesResponse)(nil),    // 2: economizador.v1.ListDevicesResponse
+	(*GetStatusRequest)(nil),       // 3: economizador.v1.GetStatusRequest
+	(*DeviceStatus)(nil),           // 4: economizador.v1.DeviceStatus
+	(*SetTemperatureRequest)(nil),  // 5: economizador.v1.SetTemperatureRequest
+	(*SetTemperatureResponse)(nil), // 6: economizador.v1.SetTemperatureResponse
+	(*StreamEventsRequest)(nil),    // 7: economizador.v1.StreamEventsRequest
+	(*DeviceEvent)(nil),            // 8: economizador.v1.DeviceEvent
+}
+var file_economizador_v1_economizador_proto_depIdxs = []int32{
+	1, // 0: economizador.v1.ListDevicesResponse.devices:type_name -> economizador.v1.Device
+	0, // 1: economizador.v1.EconomizadorService.ListDevices:input_type -> economizador.v1.ListDevicesRequest
+	3, // 2: economizador.v1.EconomizadorService.GetStatus:input_type -> economizador.v1.GetStatusRequest
+	5, // 3: economizador.v1.EconomizadorService.SetTemperature:input_type -> economizador.v1.SetTemperatureRequest
+	7, // 4: economizador.v1.EconomizadorService.StreamEvents:input_type -> economizador.v1.StreamEventsRequest
+	2, // 5: economizador.v1.EconomizadorService.ListDevices:output_type -> economizador.v1.ListDevicesResponse
+	4, // 6: economizador.v1.EconomizadorService.GetStatus:output_type -> economizador.v1.DeviceStatus
+	6, // 7: economizador.v1.EconomizadorService.SetTemperature:output_type -> economizador.v1.SetTemperatureResponse
+	8, // 8: economizador.v1.EconomizadorService.StreamEvents:output_type -> economizador.v1.DeviceEvent
+	5, // [5:9] is the sub-list for method output_type
+	1, // [1:5] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_economizador_v1_economizador_proto_init() }
+func file_economizador_v1_economizador_proto_init() {
+	if File_economizador_v1_economizador_proto != nil {
+		return
+	}
+	file_economizador_v1_economizador_proto_msgTypes[4].OneofWrappers = []any{}
+	file_economizador_v1_economizador_proto_msgTypes[8].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_economizador_v1_economizador_proto_rawDesc), len(file_economizador_v1_economizador_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   9,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_economizador_v1_economizador_proto_goTypes,
+		DependencyIndexes: file_economizador_v1_economizador_proto_depIdxs,
+		MessageInfos:      file_economizador_v1_economizador_proto_msgTypes,
+	}.Build()
+	File_economizador_v1_economizador_proto = out.File
+	file_economizador_v1_economizador_proto_goTypes = nil
+	file_economizador_v1_economizador_proto_depIdxs = nil
+}
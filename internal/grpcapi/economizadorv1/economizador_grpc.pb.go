@@ -0,0 +1,255 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.2
+// - protoc             (unknown)
+// source: economizador/v1/economizador.proto
+
+package economizadorv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	EconomizadorService_ListDevices_FullMethodName    = "/economizador.v1.EconomizadorService/ListDevices"
+	EconomizadorService_GetStatus_FullMethodName      = "/economizador.v1.EconomizadorService/GetStatus"
+	EconomizadorService_SetTemperature_FullMethodName = "/economizador.v1.EconomizadorService/SetTemperature"
+	EconomizadorService_StreamEvents_FullMethodName   = "/economizador.v1.EconomizadorService/StreamEvents"
+)
+
+// EconomizadorServiceClient is the client API for EconomizadorService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// EconomizadorService is the daemon's gRPC interface for programmatic
+// integrations: device inspection, temperature control and a server-side
+// stream of device events.
+type EconomizadorServiceClient interface {
+	// ListDevices returns every known device with its resolved policy.
+	ListDevices(ctx context.Context, in *ListDevicesRequest, opts ...grpc.CallOption) (*ListDevicesResponse, error)
+	// GetStatus polls the current state of one device.
+	GetStatus(ctx context.Context, in *GetStatusRequest, opts ...grpc.CallOption) (*DeviceStatus, error)
+	// SetTemperature sets the target temperature of one device.
+	SetTemperature(ctx context.Context, in *SetTemperatureRequest, opts ...grpc.CallOption) (*SetTemperatureResponse, error)
+	// StreamEvents streams every device status event as it arrives.
+	StreamEvents(ctx context.Context, in *StreamEventsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[DeviceEvent], error)
+}
+
+type economizadorServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewEconomizadorServiceClient(cc grpc.ClientConnInterface) EconomizadorServiceClient {
+	return &economizadorServiceClient{cc}
+}
+
+func (c *economizadorServiceClient) ListDevices(ctx context.Context, in *ListDevicesRequest, opts ...grpc.CallOption) (*ListDevicesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListDevicesResponse)
+	err := c.cc.Invoke(ctx, EconomizadorService_ListDevices_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *economizadorServiceClient) GetStatus(ctx context.Context, in *GetStatusRequest, opts ...grpc.CallOption) (*DeviceStatus, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeviceStatus)
+	err := c.cc.Invoke(ctx, EconomizadorService_GetStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *economizadorServiceClient) SetTemperature(ctx context.Context, in *SetTemperatureRequest, opts ...grpc.CallOption) (*SetTemperatureResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetTemperatureResponse)
+	err := c.cc.Invoke(ctx, EconomizadorService_SetTemperature_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *economizadorServiceClient) StreamEvents(ctx context.Context, in *StreamEventsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[DeviceEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &EconomizadorService_ServiceDesc.Streams[0], EconomizadorService_StreamEvents_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamEventsRequest, DeviceEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type EconomizadorService_StreamEventsClient = grpc.ServerStreamingClient[DeviceEvent]
+
+// EconomizadorServiceServer is the server API for EconomizadorService service.
+// All implementations must embed UnimplementedEconomizadorServiceServer
+// for forward compatibility.
+//
+// EconomizadorService is the daemon's gRPC interface for programmatic
+// integrations: device inspection, temperature control and a server-side
+// stream of device events.
+type EconomizadorServiceServer interface {
+	// ListDevices returns every known device with its resolved policy.
+	ListDevices(context.Context, *ListDevicesRequest) (*ListDevicesResponse, error)
+	// GetStatus polls the current state of one device.
+	GetStatus(context.Context, *GetStatusRequest) (*DeviceStatus, error)
+	// SetTemperature sets the target temperature of one device.
+	SetTemperature(context.Context, *SetTemperatureRequest) (*SetTemperatureResponse, error)
+	// StreamEvents streams every device status event as it arrives.
+	StreamEvents(*StreamEventsRequest, grpc.ServerStreamingServer[DeviceEvent]) error
+	mustEmbedUnimplementedEconomizadorServiceServer()
+}
+
+// UnimplementedEconomizadorServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedEconomizadorServiceServer struct{}
+
+func (UnimplementedEconomizadorServiceServer) ListDevices(context.Context, *ListDevicesRequest) (*ListDevicesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListDevices not implemented")
+}
+func (UnimplementedEconomizadorServiceServer) GetStatus(context.Context, *GetStatusRequest) (*DeviceStatus, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetStatus not implemented")
+}
+func (UnimplementedEconomizadorServiceServer) SetTemperature(context.Context, *SetTemperatureRequest) (*SetTemperatureResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method SetTemperature not implemented")
+}
+func (UnimplementedEconomizadorServiceServer) StreamEvents(*StreamEventsRequest, grpc.ServerStreamingServer[DeviceEvent]) error {
+	return status.Error(codes.Unimplemented, "method StreamEvents not implemented")
+}
+func (UnimplementedEconomizadorServiceServer) mustEmbedUnimplementedEconomizadorServiceServer() {}
+func (UnimplementedEconomizadorServiceServer) testEmbeddedByValue()                             {}
+
+// UnsafeEconomizadorServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to EconomizadorServiceServer will
+// result in compilation errors.
+type UnsafeEconomizadorServiceServer interface {
+	mustEmbedUnimplementedEconomizadorServiceServer()
+}
+
+func RegisterEconomizadorServiceServer(s grpc.ServiceRegistrar, srv EconomizadorServiceServer) {
+	// If the following call panics, it indicates UnimplementedEconomizadorServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&EconomizadorService_ServiceDesc, srv)
+}
+
+func _EconomizadorService_ListDevices_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDevicesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EconomizadorServiceServer).ListDevices(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EconomizadorService_ListDevices_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EconomizadorServiceServer).ListDevices(ctx, req.(*ListDevicesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EconomizadorService_GetStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EconomizadorServiceServer).GetStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EconomizadorService_GetStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EconomizadorServiceServer).GetStatus(ctx, req.(*GetStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EconomizadorService_SetTemperature_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetTemperatureRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EconomizadorServiceServer).SetTemperature(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: EconomizadorService_SetTemperature_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EconomizadorServiceServer).SetTemperature(ctx, req.(*SetTemperatureRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EconomizadorService_StreamEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamEventsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(EconomizadorServiceServer).StreamEvents(m, &grpc.GenericServerStream[StreamEventsRequest, DeviceEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type EconomizadorService_StreamEventsServer = grpc.ServerStreamingServer[DeviceEvent]
+
+// EconomizadorService_ServiceDesc is the grpc.ServiceDesc for EconomizadorService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var EconomizadorService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "economizador.v1.EconomizadorService",
+	HandlerType: (*EconomizadorServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListDevices",
+			Handler:    _EconomizadorService_ListDevices_Handler,
+		},
+		{
+			MethodName: "GetStatus",
+			Handler:    _EconomizadorService_GetStatus_Handler,
+		},
+		{
+			MethodName: "SetTemperature",
+			Handler:    _EconomizadorService_SetTemperature_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamEvents",
+			Handler:       _EconomizadorService_StreamEvents_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "economizador/v1/economizador.proto",
+}
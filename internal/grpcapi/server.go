@@ -0,0 +1,185 @@
+// Package grpcapi exposes the daemon's gRPC service for programmatic
+// integrations, defined in api/economizador/v1/economizador.proto.
+package grpcapi
+
+import (
+	"context"
+	"controle-arcondicionado/internal/grpcapi/economizadorv1"
+	"controle-arcondicionado/internal/rules"
+	"controle-arcondicionado/internal/thinq"
+	"fmt"
+	"net"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Server implements the EconomizadorService. Events arriving from ThinQ
+// are fanned out to every active StreamEvents subscriber.
+type Server struct {
+	economizadorv1.UnimplementedEconomizadorServiceServer
+
+	engine  *rules.Engine
+	client  *thinq.Client
+	devices []thinq.Device
+	logf    func(format string, args ...interface{})
+
+	mu          sync.Mutex
+	subscribers map[chan *economizadorv1.DeviceEvent]struct{}
+}
+
+// NewServer creates a gRPC server around the rules engine and ThinQ client.
+func NewServer(engine *rules.Engine, client *thinq.Client, devices []thinq.Device, logf func(format string, args ...interface{})) *Server {
+	return &Server{
+		engine:      engine,
+		client:      client,
+		devices:     devices,
+		logf:        logf,
+		subscribers: make(map[chan *economizadorv1.DeviceEvent]struct{}),
+	}
+}
+
+// Start begins serving on the given address in a background goroutine.
+// The returned function stops the server gracefully.
+func (s *Server) Start(listen string) (func(), error) {
+	listener, err := net.Listen("tcp", listen)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", listen, err)
+	}
+
+	grpcServer := grpc.NewServer()
+	economizadorv1.RegisterEconomizadorServiceServer(grpcServer, s)
+
+	go func() {
+		if err := grpcServer.Serve(listener); err != nil {
+			s.logf("gRPC server error: %v", err)
+		}
+	}()
+
+	s.logf("gRPC API listening on %s", listen)
+	return grpcServer.GracefulStop, nil
+}
+
+// Broadcast fans a device event out to every active stream subscriber.
+// Slow subscribers have events dropped rather than blocking the handler.
+func (s *Server) Broadcast(event *thinq.Event) {
+	if event.PushType != "DEVICE_STATUS" {
+		return
+	}
+
+	deviceEvent := &economizadorv1.DeviceEvent{
+		DeviceId:           event.DeviceID,
+		Alias:              s.alias(event.DeviceID),
+		PushType:           event.PushType,
+		TargetTemperature:  event.TargetTemperature,
+		CurrentTemperature: event.CurrentTemperature,
+		JobMode:            event.JobMode,
+		PowerOn:            event.PowerOn,
+		ErrorCode:          event.ErrorCode,
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for subscriber := range s.subscribers {
+		select {
+		case subscriber <- deviceEvent:
+		default:
+		}
+	}
+}
+
+// ListDevices returns every known device with its resolved policy.
+func (s *Server) ListDevices(ctx context.Context, _ *economizadorv1.ListDevicesRequest) (*economizadorv1.ListDevicesResponse, error) {
+	response := &economizadorv1.ListDevicesResponse{}
+	for _, device := range s.devices {
+		policy := s.engine.Resolver().PolicyFor(device.DeviceID)
+		response.Devices = append(response.Devices, &economizadorv1.Device{
+			DeviceId:       device.DeviceID,
+			Alias:          device.Alias,
+			Zone:           s.engine.Resolver().ZoneOf(device.DeviceID),
+			MinTemperature: int32(policy.MinTemperature),
+			Mode:           string(s.engine.ModeOf(device.DeviceID)),
+		})
+	}
+	return response, nil
+}
+
+// GetStatus polls the current state of one device.
+func (s *Server) GetStatus(ctx context.Context, req *economizadorv1.GetStatusRequest) (*economizadorv1.DeviceStatus, error) {
+	deviceID, err := s.resolveDevice(req.GetDevice())
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+
+	state, err := s.client.GetDeviceState(deviceID)
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "failed to poll device state: %v", err)
+	}
+
+	event := thinq.EventFromState(deviceID, state)
+	return &economizadorv1.DeviceStatus{
+		DeviceId:           deviceID,
+		Alias:              s.alias(deviceID),
+		PowerOn:            event.PowerOn,
+		JobMode:            event.JobMode,
+		TargetTemperature:  event.TargetTemperature,
+		CurrentTemperature: event.CurrentTemperature,
+		ErrorCode:          event.ErrorCode,
+	}, nil
+}
+
+// SetTemperature sets the target temperature of one device.
+func (s *Server) SetTemperature(ctx context.Context, req *economizadorv1.SetTemperatureRequest) (*economizadorv1.SetTemperatureResponse, error) {
+	deviceID, err := s.resolveDevice(req.GetDevice())
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+
+	if err := s.client.SetTemperature(deviceID, int(req.GetTemperature())); err != nil {
+		return nil, status.Errorf(codes.Unavailable, "failed to set temperature: %v", err)
+	}
+	s.logf("[gRPC] Temperature of %s set to %d°C", s.alias(deviceID), req.GetTemperature())
+	return &economizadorv1.SetTemperatureResponse{}, nil
+}
+
+// StreamEvents streams device status events until the client disconnects.
+func (s *Server) StreamEvents(_ *economizadorv1.StreamEventsRequest, stream economizadorv1.EconomizadorService_StreamEventsServer) error {
+	events := make(chan *economizadorv1.DeviceEvent, 64)
+
+	s.mu.Lock()
+	s.subscribers[events] = struct{}{}
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.subscribers, events)
+		s.mu.Unlock()
+	}()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case event := <-events:
+			if err := stream.Send(event); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// resolveDevice accepts a device ID, an alias or a unique alias prefix.
+func (s *Server) resolveDevice(nameOrID string) (string, error) {
+	return thinq.ResolveDevice(s.devices, nameOrID)
+}
+
+// alias returns the friendly name of a device, falling back to its ID.
+func (s *Server) alias(deviceID string) string {
+	for _, device := range s.devices {
+		if device.DeviceID == deviceID {
+			return device.Alias
+		}
+	}
+	return deviceID
+}
@@ -0,0 +1,145 @@
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+)
+
+// aggregateKey identifies one hourly rollup bucket.
+type aggregateKey struct {
+	hour     time.Time
+	deviceID string
+}
+
+// Compact rolls raw entries older than the retention window up into hourly
+// per-device aggregates (kind "hourly"), rewriting the file atomically, so
+// it does not grow unbounded on small SD cards. Existing rollups inside the
+// window are carried over; ones outside it are merged into their bucket.
+// It returns how many raw entries were rolled up.
+func (s *Store) Compact(retention time.Duration) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := s.readAllLocked()
+	if err != nil {
+		return 0, err
+	}
+
+	cutoff := time.Now().Add(-retention)
+	aggregates := make(map[aggregateKey]*Entry)
+	var recent []Entry
+	compacted := 0
+	for _, entry := range entries {
+		if !entry.Timestamp.Before(cutoff) {
+			recent = append(recent, entry)
+			continue
+		}
+
+		key := aggregateKey{hour: entry.Timestamp.Truncate(time.Hour), deviceID: entry.DeviceID}
+		aggregate, ok := aggregates[key]
+		if !ok {
+			aggregate = &Entry{
+				Timestamp: key.hour,
+				Kind:      KindHourly,
+				DeviceID:  entry.DeviceID,
+				Alias:     entry.Alias,
+				Counts:    make(map[string]int),
+			}
+			aggregates[key] = aggregate
+		}
+
+		if entry.Kind == KindHourly {
+			for kind, count := range entry.Counts {
+				aggregate.Counts[kind] += count
+			}
+		} else {
+			aggregate.Counts[entry.Kind]++
+			compacted++
+		}
+	}
+
+	if compacted == 0 {
+		return 0, nil // nothing old enough; avoid rewriting the file
+	}
+
+	keys := make([]aggregateKey, 0, len(aggregates))
+	for key := range aggregates {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if !keys[i].hour.Equal(keys[j].hour) {
+			return keys[i].hour.Before(keys[j].hour)
+		}
+		return keys[i].deviceID < keys[j].deviceID
+	})
+
+	// Rewrite atomically: aggregates first, then the raw window
+	tmp := s.path + ".tmp"
+	file, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create compacted history: %w", err)
+	}
+	enc := json.NewEncoder(file)
+	for _, key := range keys {
+		if err := enc.Encode(aggregates[key]); err != nil {
+			file.Close()
+			return 0, fmt.Errorf("failed to write compacted history: %w", err)
+		}
+	}
+	for _, entry := range recent {
+		if err := enc.Encode(entry); err != nil {
+			file.Close()
+			return 0, fmt.Errorf("failed to write compacted history: %w", err)
+		}
+	}
+	if err := file.Close(); err != nil {
+		return 0, fmt.Errorf("failed to write compacted history: %w", err)
+	}
+
+	if err := os.Rename(tmp, s.path); err != nil {
+		return 0, fmt.Errorf("failed to replace history file: %w", err)
+	}
+
+	// Reopen the append handle on the new file
+	s.file.Close()
+	appendFile, err := os.OpenFile(s.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return 0, fmt.Errorf("failed to reopen history file: %w", err)
+	}
+	s.file = appendFile
+	s.enc = json.NewEncoder(appendFile)
+
+	return compacted, nil
+}
+
+// readAllLocked reads every entry in the file. Callers must hold s.mu.
+func (s *Store) readAllLocked() ([]Entry, error) {
+	file, err := os.Open(s.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history file: %w", err)
+	}
+	defer file.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var entry Entry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			continue // skip malformed lines, same as ReadSince
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read history file: %w", err)
+	}
+	return entries, nil
+}
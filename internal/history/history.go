@@ -0,0 +1,111 @@
+// Package history keeps an append-only NDJSON log of policy-relevant
+// activity (violations, corrections, power changes). It is the data source
+// for the savings reports and usage statistics.
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// Entry kinds recorded in the store.
+const (
+	KindViolation  = "violation"  // a target temperature below the minimum was seen
+	KindCorrection = "correction" // the daemon adjusted a device
+	KindPower      = "power"      // a device turned on or off
+	KindEscalation = "escalation" // repeated corrections triggered an escalation
+	KindHourly     = "hourly"     // per-hour rollup of compacted raw entries
+)
+
+// Entry is one record in the history store.
+type Entry struct {
+	Timestamp time.Time `json:"timestamp"`
+	Kind      string    `json:"kind"`
+	DeviceID  string    `json:"deviceId"`
+	Alias     string    `json:"alias,omitempty"`
+	Target    *float64  `json:"target,omitempty"`
+	Minimum   *int      `json:"minimum,omitempty"`
+	PowerOn   *bool     `json:"powerOn,omitempty"`
+
+	// Counts is only set on hourly rollup entries: raw entry count per kind.
+	Counts map[string]int `json:"counts,omitempty"`
+}
+
+// Store appends entries to an NDJSON file and reads them back for reports.
+type Store struct {
+	mu   sync.Mutex
+	path string
+	file *os.File
+	enc  *json.Encoder
+}
+
+// Open opens (or creates) the history file for appending.
+func Open(path string) (*Store, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history file: %w", err)
+	}
+
+	return &Store{
+		path: path,
+		file: file,
+		enc:  json.NewEncoder(file),
+	}, nil
+}
+
+// Append writes an entry, stamping it with the current time when unset.
+func (s *Store) Append(entry Entry) error {
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = time.Now()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.enc.Encode(entry)
+}
+
+// ReadSince returns all entries recorded at or after the given time.
+func (s *Store) ReadSince(since time.Time) ([]Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := os.Open(s.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history file: %w", err)
+	}
+	defer file.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var entry Entry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			continue // skip malformed lines rather than failing the report
+		}
+		if entry.Timestamp.Before(since) {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read history file: %w", err)
+	}
+
+	return entries, nil
+}
+
+// Close closes the underlying file.
+func (s *Store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.file.Close()
+}
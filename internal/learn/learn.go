@@ -0,0 +1,188 @@
+// Package learn mines the activity history for per-device usage patterns
+// and turns them into quiet-hour profiles: windows of the day in which a
+// device is essentially never used. Suggestions are reviewed and accepted
+// through the `learn` CLI command before the daemon enforces them.
+package learn
+
+import (
+	"controle-arcondicionado/internal/history"
+	"fmt"
+	"sort"
+	"time"
+)
+
+const (
+	// minObservedDays is the minimum span of history before suggesting
+	// anything; a weekend of data says little about the week.
+	minObservedDays = 7
+	// minQuietHours is the shortest window worth suggesting.
+	minQuietHours = 4
+	// quietThreshold is the fraction of observed days a device may be
+	// active during an hour and still have it count as quiet.
+	quietThreshold = 0.05
+	// maxInterval caps a power-on interval when the off event was lost
+	// (e.g. the daemon restarted), so one gap does not mark a whole day.
+	maxInterval = 24 * time.Hour
+)
+
+// Window is a daily time window in whole hours, possibly wrapping
+// midnight: {19, 7} means 19:00 through 07:00 the next day.
+type Window struct {
+	Start int `yaml:"start"`
+	End   int `yaml:"end"`
+}
+
+// Contains reports whether the given hour of the day falls in the window.
+func (w Window) Contains(hour int) bool {
+	if w.Start <= w.End {
+		return hour >= w.Start && hour < w.End
+	}
+	return hour >= w.Start || hour < w.End
+}
+
+// Hours returns the window's length in hours.
+func (w Window) Hours() int {
+	if w.Start <= w.End {
+		return w.End - w.Start
+	}
+	return 24 - w.Start + w.End
+}
+
+func (w Window) String() string {
+	return fmt.Sprintf("%02d:00-%02d:00", w.Start, w.End)
+}
+
+// Suggestion is a quiet-hour window the analysis proposes for one device.
+type Suggestion struct {
+	DeviceID string
+	Alias    string
+	Window   Window
+	Days     int // days of history backing the suggestion
+}
+
+// usage tracks, per hour of the day, on which dates a device was active.
+type usage struct {
+	alias   string
+	byHour  [24]map[string]bool // hour → set of dates (2006-01-02)
+	onSince time.Time           // open power-on interval, zero when off
+}
+
+// Analyze mines the history entries for devices with a long stretch of
+// daily hours in which they are essentially never active, and returns one
+// suggested quiet-hour window per such device, sorted by alias.
+func Analyze(entries []history.Entry) []Suggestion {
+	devices := make(map[string]*usage)
+	dates := make(map[string]bool)
+
+	record := func(u *usage, at time.Time) {
+		date := at.Format("2006-01-02")
+		hour := at.Hour()
+		if u.byHour[hour] == nil {
+			u.byHour[hour] = make(map[string]bool)
+		}
+		u.byHour[hour][date] = true
+	}
+
+	for _, entry := range entries {
+		if entry.DeviceID == "" {
+			continue
+		}
+		at := entry.Timestamp.Local()
+		dates[at.Format("2006-01-02")] = true
+
+		u := devices[entry.DeviceID]
+		if u == nil {
+			u = &usage{}
+			devices[entry.DeviceID] = u
+		}
+		if entry.Alias != "" {
+			u.alias = entry.Alias
+		}
+
+		switch entry.Kind {
+		case history.KindPower:
+			if entry.PowerOn != nil && *entry.PowerOn {
+				if u.onSince.IsZero() {
+					u.onSince = at
+				}
+			} else if !u.onSince.IsZero() {
+				markInterval(record, u, u.onSince, at)
+				u.onSince = time.Time{}
+			}
+		default:
+			// Violations, corrections and hourly rollups all prove the
+			// device was in use around that hour
+			record(u, at)
+		}
+	}
+
+	// Close intervals still open at the end of the history
+	for _, u := range devices {
+		if !u.onSince.IsZero() {
+			markInterval(record, u, u.onSince, u.onSince.Add(time.Hour))
+		}
+	}
+
+	if len(dates) < minObservedDays {
+		return nil
+	}
+
+	var suggestions []Suggestion
+	for deviceID, u := range devices {
+		window, ok := quietWindow(u, len(dates))
+		if !ok {
+			continue
+		}
+		suggestions = append(suggestions, Suggestion{
+			DeviceID: deviceID,
+			Alias:    u.alias,
+			Window:   window,
+			Days:     len(dates),
+		})
+	}
+	sort.Slice(suggestions, func(i, j int) bool {
+		return suggestions[i].Alias < suggestions[j].Alias
+	})
+	return suggestions
+}
+
+// markInterval marks every hour of the day the interval touches as active.
+func markInterval(record func(*usage, time.Time), u *usage, from, to time.Time) {
+	if to.Sub(from) > maxInterval {
+		to = from.Add(maxInterval)
+	}
+	for at := from; at.Before(to); at = at.Add(time.Hour) {
+		record(u, at)
+	}
+	record(u, to)
+}
+
+// quietWindow finds the longest run of hours (wrapping midnight) in which
+// the device was active on at most quietThreshold of the observed days.
+func quietWindow(u *usage, observedDays int) (Window, bool) {
+	quiet := [24]bool{}
+	for hour := 0; hour < 24; hour++ {
+		active := len(u.byHour[hour])
+		quiet[hour] = float64(active)/float64(observedDays) <= quietThreshold
+	}
+
+	bestStart, bestLength := 0, 0
+	for start := 0; start < 24; start++ {
+		if !quiet[start] {
+			continue
+		}
+		length := 0
+		for length < 24 && quiet[(start+length)%24] {
+			length++
+		}
+		if length > bestLength {
+			bestStart, bestLength = start, length
+		}
+	}
+
+	if bestLength < minQuietHours || bestLength == 24 {
+		// An always-quiet device has no pattern worth enforcing
+		return Window{}, false
+	}
+	return Window{Start: bestStart, End: (bestStart + bestLength) % 24}, true
+}
@@ -0,0 +1,55 @@
+package learn
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultProfilesFile is where accepted profiles live, next to config.yaml.
+const DefaultProfilesFile = "profiles.yaml"
+
+// Profile is an accepted quiet-hour window for one device, referenced by
+// alias like the rest of the YAML configuration.
+type Profile struct {
+	Device string `yaml:"device"`
+	Off    Window `yaml:"off_hours"` // hours in which the device stays off
+}
+
+// profilesFile is the on-disk shape of the accepted profiles.
+type profilesFile struct {
+	Profiles []Profile `yaml:"profiles"`
+}
+
+// LoadProfiles reads the accepted profiles. A missing file returns nil
+// without error, so the daemon runs unchanged until something is accepted.
+func LoadProfiles(path string) ([]Profile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read profiles file: %w", err)
+	}
+
+	var file profilesFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse profiles file %s: %w", path, err)
+	}
+	return file.Profiles, nil
+}
+
+// SaveProfiles writes the accepted profiles.
+func SaveProfiles(path string, profiles []Profile) error {
+	data, err := yaml.Marshal(profilesFile{Profiles: profiles})
+	if err != nil {
+		return fmt.Errorf("failed to marshal profiles: %w", err)
+	}
+	header := "# Perfis de horário aprendidos a partir do histórico de uso.\n" +
+		"# Gerenciado por `economizador learn`; edite com cuidado.\n"
+	if err := os.WriteFile(path, append([]byte(header), data...), 0644); err != nil {
+		return fmt.Errorf("failed to write profiles file: %w", err)
+	}
+	return nil
+}
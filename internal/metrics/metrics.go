@@ -0,0 +1,121 @@
+// Package metrics exports room climate gauges in the Prometheus text
+// exposition format, served from the daemon's local API under /metrics.
+package metrics
+
+import (
+	"controle-arcondicionado/internal/statecache"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Collector renders the shared state cache as Prometheus gauges labeled
+// by device alias and zone.
+type Collector struct {
+	mu         sync.Mutex
+	states     *statecache.Cache
+	zoneOf     func(deviceID string) string
+	queueDepth func() int
+	mismatches func() int64
+}
+
+// NewCollector creates a collector reading from the state cache. zoneOf
+// maps a device ID to its zone name (empty when the device has no zone).
+func NewCollector(states *statecache.Cache, zoneOf func(deviceID string) string) *Collector {
+	return &Collector{states: states, zoneOf: zoneOf}
+}
+
+// SetQueueDepth registers a callback reporting how many MQTT messages are
+// waiting in the handler pool, exported as thinq_handler_queue_depth.
+func (c *Collector) SetQueueDepth(depth func() int) {
+	c.mu.Lock()
+	c.queueDepth = depth
+	c.mu.Unlock()
+}
+
+// SetSchemaMismatches registers a callback reporting how many API
+// responses failed to match their endpoint's primary schema, exported as
+// thinq_schema_mismatch_total.
+func (c *Collector) SetSchemaMismatches(mismatches func() int64) {
+	c.mu.Lock()
+	c.mismatches = mismatches
+	c.mu.Unlock()
+}
+
+// ServeHTTP renders the gauges in the Prometheus text format.
+func (c *Collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	states := c.states.All()
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+
+	c.writeGauge(w, "thinq_current_temperature", "Current room temperature in degrees Celsius.",
+		states, func(s *statecache.DeviceState) (float64, bool) {
+			if s.CurrentTemperature == nil {
+				return 0, false
+			}
+			return *s.CurrentTemperature, true
+		})
+	c.writeGauge(w, "thinq_target_temperature", "Target temperature (setpoint) in degrees Celsius.",
+		states, func(s *statecache.DeviceState) (float64, bool) {
+			if s.TargetTemperature == nil {
+				return 0, false
+			}
+			return *s.TargetTemperature, true
+		})
+	c.writeGauge(w, "thinq_power_state", "Whether the unit is powered on (1) or off (0).",
+		states, func(s *statecache.DeviceState) (float64, bool) {
+			if s.PowerOn == nil {
+				return 0, false
+			}
+			if *s.PowerOn {
+				return 1, true
+			}
+			return 0, true
+		})
+	c.writeGauge(w, "thinq_state_stale", "Whether the device's cached state is stale (1) or fresh (0).",
+		states, func(s *statecache.DeviceState) (float64, bool) {
+			if s.Stale {
+				return 1, true
+			}
+			return 0, true
+		})
+
+	c.mu.Lock()
+	queueDepth := c.queueDepth
+	mismatches := c.mismatches
+	c.mu.Unlock()
+	if queueDepth != nil {
+		fmt.Fprintf(w, "# HELP thinq_handler_queue_depth Number of MQTT messages waiting in the handler pool.\n")
+		fmt.Fprintf(w, "# TYPE thinq_handler_queue_depth gauge\n")
+		fmt.Fprintf(w, "thinq_handler_queue_depth %d\n", queueDepth())
+	}
+	if mismatches != nil {
+		fmt.Fprintf(w, "# HELP thinq_schema_mismatch_total API responses that did not match the expected schema.\n")
+		fmt.Fprintf(w, "# TYPE thinq_schema_mismatch_total counter\n")
+		fmt.Fprintf(w, "thinq_schema_mismatch_total %d\n", mismatches())
+	}
+}
+
+// writeGauge renders one gauge family, skipping devices without a value.
+func (c *Collector) writeGauge(w http.ResponseWriter, name, help string,
+	states []statecache.DeviceState, value func(*statecache.DeviceState) (float64, bool)) {
+	fmt.Fprintf(w, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(w, "# TYPE %s gauge\n", name)
+	for i := range states {
+		state := &states[i]
+		v, ok := value(state)
+		if !ok {
+			continue
+		}
+		fmt.Fprintf(w, "%s{device=\"%s\",zone=\"%s\"} %g\n",
+			name, escapeLabel(state.Alias), escapeLabel(c.zoneOf(state.DeviceID)), v)
+	}
+}
+
+// escapeLabel escapes a label value per the Prometheus text format.
+func escapeLabel(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `"`, `\"`)
+	return strings.ReplaceAll(value, "\n", `\n`)
+}
@@ -0,0 +1,87 @@
+// Package notify delivers human-facing notifications about policy events,
+// either to the daemon log or to an external webhook (ntfy, Slack, etc.).
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Notifier sends a short notification message.
+type Notifier interface {
+	Send(title, message string) error
+}
+
+// UrgentSender is implemented by notifiers that can deliver on a
+// high-priority channel. Callers fall back to Send when unsupported.
+type UrgentSender interface {
+	SendUrgent(title, message string) error
+}
+
+// LogNotifier writes notifications to the daemon log. It is the fallback
+// when no external notifier is configured.
+type LogNotifier struct {
+	Logf func(format string, args ...interface{})
+}
+
+// Send logs the notification.
+func (n *LogNotifier) Send(title, message string) error {
+	n.Logf("[notification] %s: %s", title, message)
+	return nil
+}
+
+// WebhookNotifier POSTs notifications as JSON to a configured URL.
+type WebhookNotifier struct {
+	URL        string
+	httpClient *http.Client
+}
+
+// NewWebhookNotifier creates a notifier posting to the given URL.
+func NewWebhookNotifier(url string) *WebhookNotifier {
+	return &WebhookNotifier{
+		URL: url,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// Send posts {"title": ..., "message": ...} to the webhook URL.
+func (n *WebhookNotifier) Send(title, message string) error {
+	return n.post(title, message, "")
+}
+
+// SendUrgent posts the notification with "priority": "high", which ntfy
+// and similar services map to their high-priority channel.
+func (n *WebhookNotifier) SendUrgent(title, message string) error {
+	return n.post(title, message, "high")
+}
+
+func (n *WebhookNotifier) post(title, message, priority string) error {
+	body := map[string]string{
+		"title":   title,
+		"message": message,
+	}
+	if priority != "" {
+		body["priority"] = priority
+	}
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal notification: %w", err)
+	}
+
+	resp, err := n.httpClient.Post(n.URL, "application/json", bytes.NewBuffer(payload))
+	if err != nil {
+		return fmt.Errorf("failed to send notification: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("notification webhook returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,173 @@
+// Package presence detects whether anyone is home, either by asking Home
+// Assistant about its person entities or by pinging known phones on the
+// LAN. The daemon uses it to apply an "away" policy automatically.
+package presence
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os/exec"
+	"time"
+)
+
+// Source reports whether someone is currently home.
+type Source interface {
+	Home() (bool, error)
+}
+
+// HomeAssistantSource checks person entities through the Home Assistant
+// REST API: anyone with state "home" counts as present.
+type HomeAssistantSource struct {
+	baseURL    string
+	token      string
+	entities   []string
+	httpClient *http.Client
+}
+
+// NewHomeAssistantSource creates a source for the given Home Assistant
+// instance and person entity IDs (e.g. "person.rafael").
+func NewHomeAssistantSource(baseURL, token string, entities []string) *HomeAssistantSource {
+	return &HomeAssistantSource{
+		baseURL:  baseURL,
+		token:    token,
+		entities: entities,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// Home returns true when any configured person entity reports "home".
+func (s *HomeAssistantSource) Home() (bool, error) {
+	for _, entity := range s.entities {
+		state, err := s.entityState(entity)
+		if err != nil {
+			return false, err
+		}
+		if state == "home" {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *HomeAssistantSource) entityState(entity string) (string, error) {
+	url := fmt.Sprintf("%s/api/states/%s", s.baseURL, entity)
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", s.token))
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to query Home Assistant: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response body: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("Home Assistant returned status %d for %s: %s", resp.StatusCode, entity, string(body))
+	}
+
+	var state struct {
+		State string `json:"state"`
+	}
+	if err := json.Unmarshal(body, &state); err != nil {
+		return "", fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return state.State, nil
+}
+
+// PingSource considers someone home when any configured host (typically a
+// phone's static IP) answers a ping.
+type PingSource struct {
+	hosts []string
+}
+
+// NewPingSource creates a source pinging the given hosts.
+func NewPingSource(hosts []string) *PingSource {
+	return &PingSource{hosts: hosts}
+}
+
+// Home returns true when any host answers a single ping.
+func (s *PingSource) Home() (bool, error) {
+	for _, host := range s.hosts {
+		// One packet with a short timeout; phones asleep may need the
+		// watcher's debounce to avoid flapping
+		if err := exec.Command("ping", "-c", "1", "-W", "2", host).Run(); err == nil {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Watcher polls a source and invokes onChange on home/away transitions.
+// It requires missedBeforeAway consecutive "away" polls before reporting
+// away, so a phone briefly dropping off Wi-Fi doesn't trigger the policy.
+type Watcher struct {
+	source           Source
+	interval         time.Duration
+	missedBeforeAway int
+	onChange         func(home bool)
+	logf             func(format string, args ...interface{})
+}
+
+// NewWatcher creates a watcher polling the source at the given interval.
+func NewWatcher(source Source, interval time.Duration, onChange func(home bool), logf func(format string, args ...interface{})) *Watcher {
+	return &Watcher{
+		source:           source,
+		interval:         interval,
+		missedBeforeAway: 3,
+		onChange:         onChange,
+		logf:             logf,
+	}
+}
+
+// Start begins polling in a background goroutine and returns a stop function.
+func (w *Watcher) Start() func() {
+	done := make(chan struct{})
+	go func() {
+		home := true // assume someone is home until proven otherwise
+		misses := 0
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+
+			present, err := w.source.Home()
+			if err != nil {
+				w.logf("Presence check failed: %v", err)
+				continue
+			}
+
+			if present {
+				misses = 0
+				if !home {
+					home = true
+					w.onChange(true)
+				}
+				continue
+			}
+
+			misses++
+			if home && misses >= w.missedBeforeAway {
+				home = false
+				w.onChange(false)
+			}
+		}
+	}()
+
+	return func() { close(done) }
+}
@@ -0,0 +1,117 @@
+// Package report summarizes the history store into human-readable savings
+// reports, sent on a schedule through the notifier or printed on demand.
+package report
+
+import (
+	"controle-arcondicionado/internal/history"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// kWhPerDegreeHour is a rough estimate of the extra energy an AC spends per
+// degree of deeper cooling per hour. Used only to give the reports an
+// order-of-magnitude savings figure.
+const kWhPerDegreeHour = 0.12
+
+// deviceSummary aggregates one device's activity over the report period.
+type deviceSummary struct {
+	alias            string
+	corrections      int
+	violationMinutes float64
+	powerOnMinutes   float64
+	kWhAvoided       float64
+}
+
+// Generate builds a report over the entries between since and until.
+// daemonStart, when non-zero, is included as the daemon's uptime.
+func Generate(entries []history.Entry, since, until time.Time, daemonStart time.Time) string {
+	summaries := make(map[string]*deviceSummary)
+	get := func(entry history.Entry) *deviceSummary {
+		s, ok := summaries[entry.DeviceID]
+		if !ok {
+			s = &deviceSummary{alias: entry.Alias}
+			if s.alias == "" {
+				s.alias = entry.DeviceID
+			}
+			summaries[entry.DeviceID] = s
+		}
+		return s
+	}
+
+	violationStart := make(map[string]time.Time)
+	powerOnStart := make(map[string]time.Time)
+
+	for _, entry := range entries {
+		s := get(entry)
+		switch entry.Kind {
+		case history.KindViolation:
+			if _, open := violationStart[entry.DeviceID]; !open {
+				violationStart[entry.DeviceID] = entry.Timestamp
+			}
+		case history.KindCorrection:
+			s.corrections++
+			if start, open := violationStart[entry.DeviceID]; open {
+				s.violationMinutes += entry.Timestamp.Sub(start).Minutes()
+				delete(violationStart, entry.DeviceID)
+			}
+			if entry.Target != nil && entry.Minimum != nil {
+				degrees := float64(*entry.Minimum) - *entry.Target
+				if degrees > 0 {
+					// Assume the deeper setpoint would have held for an hour
+					s.kWhAvoided += degrees * kWhPerDegreeHour
+				}
+			}
+		case history.KindPower:
+			if entry.PowerOn == nil {
+				continue
+			}
+			if *entry.PowerOn {
+				if _, open := powerOnStart[entry.DeviceID]; !open {
+					powerOnStart[entry.DeviceID] = entry.Timestamp
+				}
+			} else if start, open := powerOnStart[entry.DeviceID]; open {
+				s.powerOnMinutes += entry.Timestamp.Sub(start).Minutes()
+				delete(powerOnStart, entry.DeviceID)
+			}
+		}
+	}
+
+	// Close intervals still open at the end of the period
+	for deviceID, start := range violationStart {
+		summaries[deviceID].violationMinutes += until.Sub(start).Minutes()
+	}
+	for deviceID, start := range powerOnStart {
+		summaries[deviceID].powerOnMinutes += until.Sub(start).Minutes()
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Savings report %s — %s\n",
+		since.Format("2006-01-02 15:04"), until.Format("2006-01-02 15:04"))
+
+	if len(summaries) == 0 {
+		b.WriteString("No activity recorded in this period.\n")
+	}
+
+	// Stable device order for readable output
+	deviceIDs := make([]string, 0, len(summaries))
+	for deviceID := range summaries {
+		deviceIDs = append(deviceIDs, deviceID)
+	}
+	sort.Slice(deviceIDs, func(i, j int) bool {
+		return summaries[deviceIDs[i]].alias < summaries[deviceIDs[j]].alias
+	})
+
+	for _, deviceID := range deviceIDs {
+		s := summaries[deviceID]
+		fmt.Fprintf(&b, "- %s: %d correction(s), %.0f min in violation, %.0f min powered on, ~%.2f kWh avoided\n",
+			s.alias, s.corrections, s.violationMinutes, s.powerOnMinutes, s.kWhAvoided)
+	}
+
+	if !daemonStart.IsZero() {
+		fmt.Fprintf(&b, "Daemon uptime: %s\n", until.Sub(daemonStart).Round(time.Minute))
+	}
+
+	return b.String()
+}
@@ -0,0 +1,25 @@
+package rules
+
+import "fmt"
+
+// Action is the corrective control call the engine makes when a device
+// violates its minimum temperature (and the mode is enforce).
+type Action string
+
+const (
+	// ActionAdjust raises the setpoint to the minimum (default).
+	ActionAdjust Action = "adjust"
+	// ActionFan switches the unit to FAN mode, stopping the compressor.
+	ActionFan Action = "fan"
+	// ActionPowerOff powers the unit off entirely.
+	ActionPowerOff Action = "power_off"
+)
+
+// ParseAction validates an action string.
+func ParseAction(s string) (Action, error) {
+	switch Action(s) {
+	case ActionAdjust, ActionFan, ActionPowerOff:
+		return Action(s), nil
+	}
+	return "", fmt.Errorf("invalid action %q (expected adjust, fan or power_off)", s)
+}
@@ -0,0 +1,111 @@
+package rules
+
+import (
+	"context"
+	"controle-arcondicionado/internal/thinq"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// enforceMinHumidity raises a dehumidifier target humidity that is below
+// the policy floor. Drying the air below ~45% buys little comfort and
+// keeps the compressor running, so the floor plays the same role the
+// minimum temperature does for air conditioners.
+func (e *Engine) enforceMinHumidity(ctx context.Context, event *thinq.Event) {
+	if e.types[event.DeviceID] != thinq.DeviceTypeDehumidifier || event.TargetHumidity == nil {
+		return
+	}
+
+	floor := e.resolver.PolicyFor(event.DeviceID).MinHumidity
+	target := *event.TargetHumidity
+	if floor <= 0 || int(target) >= floor {
+		return
+	}
+
+	alias := e.alias(event.DeviceID)
+	if e.relaxed(event.DeviceID) {
+		e.logf("[%s] Target humidity at %.0f%% (below minimum %d%%), but enforcement is relaxed by a scene",
+			alias, target, floor)
+		return
+	}
+
+	trace.SpanFromContext(ctx).AddEvent("humidity_violation", trace.WithAttributes(
+		attribute.Float64("thinq.target_humidity", target),
+		attribute.Int("policy.min_humidity", floor),
+	))
+
+	if !e.permitAction(event.DeviceID,
+		fmt.Sprintf("Target humidity at %.0f%% (below minimum %d%%)", target, floor),
+		"Humidity below minimum",
+		fmt.Sprintf("%s was set to %.0f%% humidity, below the minimum of %d%%", alias, target, floor)) {
+		return
+	}
+
+	e.logf("[%s] Target humidity at %.0f%% (below minimum), adjusting to %d%%...", alias, target, floor)
+	_, controlSpan := tracer.Start(ctx, "rules.set_target_humidity")
+	err := e.client.SetTargetHumidity(event.DeviceID, floor)
+	controlSpan.End()
+	if err != nil {
+		e.logf("Failed to adjust target humidity: %v", err)
+	} else {
+		e.noteCorrection(event.DeviceID)
+	}
+}
+
+// enforceAutoOff starts a countdown when a device with an auto_off_minutes
+// policy turns on and cancels it when the device turns off by itself.
+// Dehumidifiers and purifiers are easy to forget running for days; the
+// countdown powers them off after the allowed runtime.
+func (e *Engine) enforceAutoOff(event *thinq.Event) {
+	if event.PowerOn == nil {
+		return
+	}
+
+	limit := time.Duration(e.resolver.PolicyFor(event.DeviceID).AutoOffMinutes) * time.Minute
+	deviceID := event.DeviceID
+
+	e.mu.Lock()
+	timer, counting := e.autoOffTimers[deviceID]
+	switch {
+	case *event.PowerOn && !counting && limit > 0:
+		e.autoOffTimers[deviceID] = time.AfterFunc(limit, func() { e.autoOffExpired(deviceID, limit) })
+		e.mu.Unlock()
+		e.logf("[%s] Powered on, auto-off in %s", e.alias(deviceID), limit)
+	case !*event.PowerOn && counting:
+		timer.Stop()
+		delete(e.autoOffTimers, deviceID)
+		e.mu.Unlock()
+		e.logf("[%s] Powered off, auto-off countdown cancelled", e.alias(deviceID))
+	default:
+		e.mu.Unlock()
+	}
+}
+
+// autoOffExpired runs when a device has been running for its full allowed
+// time, acting according to the device's enforcement mode.
+func (e *Engine) autoOffExpired(deviceID string, limit time.Duration) {
+	e.mu.Lock()
+	delete(e.autoOffTimers, deviceID)
+	e.mu.Unlock()
+
+	alias := e.alias(deviceID)
+	if e.relaxed(deviceID) {
+		e.logf("[%s] Running for %s, but enforcement is relaxed by a scene", alias, limit)
+		return
+	}
+
+	if !e.permitAction(deviceID,
+		fmt.Sprintf("Running for %s", limit),
+		"Device running past its limit",
+		fmt.Sprintf("%s has been running for %s", alias, limit)) {
+		return
+	}
+
+	e.logf("[%s] Running for %s, powering off...", alias, limit)
+	if err := e.client.SetPower(deviceID, false); err != nil {
+		e.logf("Failed to power off %s: %v", alias, err)
+	}
+}
@@ -0,0 +1,761 @@
+package rules
+
+import (
+	"context"
+	"controle-arcondicionado/internal/config"
+	"controle-arcondicionado/internal/history"
+	"controle-arcondicionado/internal/learn"
+	"controle-arcondicionado/internal/notify"
+	"controle-arcondicionado/internal/statecache"
+	"controle-arcondicionado/internal/thinq"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracer instruments the event→rule→control pipeline.
+var tracer = otel.Tracer("controle-arcondicionado/rules")
+
+// Controller is the subset of the ThinQ client the engine uses to act on
+// devices. It allows replay and tests to substitute a dry-run client.
+type Controller interface {
+	SetTemperature(deviceID string, temperature int) error
+	SetPower(deviceID string, on bool) error
+	SetJobMode(deviceID string, mode string) error
+	SetTwoSetTemperature(deviceID string, coolTarget, heatTarget int) error
+	SetWindStrength(deviceID string, strength string) error
+	SetPowerSave(deviceID string, on bool) error
+	SetTargetHumidity(deviceID string, humidity int) error
+}
+
+// Engine applies the resolved policies to incoming device events. It keeps
+// per-device state (such as which units are running and since when) so
+// policies like the running-unit cap can act across devices.
+type Engine struct {
+	mu             sync.Mutex
+	client         Controller
+	resolver       *Resolver
+	notifier       notify.Notifier
+	devices        []thinq.Device
+	aliases        map[string]string
+	types          map[string]string // device type per device, for type-scoped rules
+	runningSince   map[string]time.Time
+	modes          map[string]Mode         // runtime mode overrides
+	ramps          map[string]*rampState   // in-progress gradual corrections
+	jetTimers      map[string]*time.Timer  // jet-mode countdowns
+	autoOffTimers  map[string]*time.Timer  // auto-off countdowns (auto_off_minutes)
+	lastRoomTemp   map[string]float64      // last reported current temperature
+	states         *statecache.Cache       // optional shared state cache
+	lastDrySwitch  map[string]time.Time    // last COOL→DRY switch per device
+	powerSaveOK    map[string]bool         // devices whose profile exposes powerSave
+	powerSaveOn    map[string]bool         // power-save already applied since power-on
+	quietHours     map[string]learn.Window // accepted learned quiet-hour profiles
+	escalateAfter  int                     // corrections/hour before escalating (0 = off)
+	escalateLock   bool                    // switch escalated devices to notify mode
+	corrections    map[string][]time.Time  // recent corrections per device
+	lastEscalation map[string]time.Time    // last escalation per device
+	lastErrors     map[string]string       // active error code per device
+	scenes         map[string]config.Scene
+	relaxUntil     map[string]time.Time // enforcement suspended until (scenes)
+	store          *history.Store       // optional activity history
+	away           bool
+	awayPolicy     AwayPolicy
+	awayUntil      time.Time
+	awayPrecool    bool // precool was requested for the scheduled return
+	awayTimers     []*time.Timer
+	logf           func(format string, args ...interface{})
+}
+
+// AwayPolicy adjusts enforcement while nobody is home.
+type AwayPolicy struct {
+	MinTemperature int  // raised minimum while away (0 = unchanged)
+	PowerOff       bool // power units off and keep them off while away
+}
+
+// NewEngine creates an engine enforcing policies through the given client.
+func NewEngine(client Controller, resolver *Resolver, devices []thinq.Device, notifier notify.Notifier, logf func(format string, args ...interface{})) *Engine {
+	aliases := make(map[string]string)
+	types := make(map[string]string)
+	for _, device := range devices {
+		aliases[device.DeviceID] = device.Alias
+		types[device.DeviceID] = device.DeviceType
+	}
+
+	return &Engine{
+		client:         client,
+		resolver:       resolver,
+		notifier:       notifier,
+		devices:        devices,
+		aliases:        aliases,
+		types:          types,
+		runningSince:   make(map[string]time.Time),
+		modes:          make(map[string]Mode),
+		ramps:          make(map[string]*rampState),
+		jetTimers:      make(map[string]*time.Timer),
+		autoOffTimers:  make(map[string]*time.Timer),
+		lastRoomTemp:   make(map[string]float64),
+		lastDrySwitch:  make(map[string]time.Time),
+		powerSaveOK:    make(map[string]bool),
+		powerSaveOn:    make(map[string]bool),
+		quietHours:     make(map[string]learn.Window),
+		corrections:    make(map[string][]time.Time),
+		lastEscalation: make(map[string]time.Time),
+		lastErrors:     make(map[string]string),
+		relaxUntil:     make(map[string]time.Time),
+		logf:           logf,
+	}
+}
+
+// SetHistory makes the engine record activity in the given store.
+func (e *Engine) SetHistory(store *history.Store) {
+	e.store = store
+}
+
+// record appends an entry to the history store when one is configured.
+func (e *Engine) record(entry history.Entry) {
+	if e.store == nil {
+		return
+	}
+	entry.Alias = e.alias(entry.DeviceID)
+	if err := e.store.Append(entry); err != nil {
+		e.logf("Failed to record history entry: %v", err)
+	}
+}
+
+// Resolver returns the engine's policy resolver.
+func (e *Engine) Resolver() *Resolver {
+	return e.resolver
+}
+
+// ModeOf returns the device's active mode: a runtime override when one was
+// set, otherwise the mode from the resolved policy.
+func (e *Engine) ModeOf(deviceID string) Mode {
+	e.mu.Lock()
+	mode, ok := e.modes[deviceID]
+	e.mu.Unlock()
+	if ok {
+		return mode
+	}
+	return e.resolver.PolicyFor(deviceID).Mode
+}
+
+// SetMode overrides a device's mode at runtime.
+func (e *Engine) SetMode(deviceID string, mode Mode) {
+	e.mu.Lock()
+	e.modes[deviceID] = mode
+	e.mu.Unlock()
+	e.logf("[%s] Mode set to %s", e.alias(deviceID), mode)
+}
+
+// permitAction applies the device's enforcement mode to a detected
+// situation: log mode only logs it, notify mode sends the notification,
+// and only enforce mode returns true so the caller may act.
+func (e *Engine) permitAction(deviceID, situation, title, message string) bool {
+	alias := e.alias(deviceID)
+	switch e.ModeOf(deviceID) {
+	case ModeLog:
+		e.logf("[%s] %s, mode is log: not acting", alias, situation)
+		return false
+	case ModeNotify:
+		e.logf("[%s] %s, mode is notify: sending notification", alias, situation)
+		if err := e.notifier.Send(title, message); err != nil {
+			e.logf("Failed to send notification: %v", err)
+		}
+		return false
+	}
+	return true
+}
+
+// HandleEvent runs a parsed device event through every enforcement rule.
+func (e *Engine) HandleEvent(event *thinq.Event) {
+	if event.PushType != "DEVICE_STATUS" {
+		return
+	}
+
+	ctx, span := tracer.Start(context.Background(), "rules.handle_event",
+		trace.WithAttributes(
+			attribute.String("thinq.device_id", event.DeviceID),
+			attribute.String("thinq.device_alias", e.alias(event.DeviceID)),
+		))
+	defer span.End()
+
+	e.trackRoomTemperature(event)
+	e.trackPower(event)
+	e.checkDeviceError(event)
+	e.enforceJetLimit(event)
+	e.enforceAway(ctx, event)
+	e.enforceMinTemperature(ctx, event)
+	e.enforceEnergySaving(ctx, event)
+	e.enforceQuietHours(ctx, event)
+	e.enforceDryMode(ctx, event)
+	e.enforceMinHumidity(ctx, event)
+	e.enforceAutoOff(event)
+	e.enforceMaxRunning(ctx, event)
+}
+
+// SetAway enters or leaves away mode. Entering with PowerOff set powers
+// every known device off immediately. Leaving cancels any scheduled return.
+func (e *Engine) SetAway(away bool, policy AwayPolicy) {
+	e.mu.Lock()
+	changed := e.away != away
+	e.away = away
+	e.awayPolicy = policy
+	if !away {
+		e.awayUntil = time.Time{}
+		e.awayPrecool = false
+		for _, timer := range e.awayTimers {
+			timer.Stop()
+		}
+		e.awayTimers = nil
+	}
+	e.mu.Unlock()
+
+	if !changed {
+		return
+	}
+
+	if !away {
+		e.logf("Away mode deactivated, normal policies restored")
+		return
+	}
+
+	e.logf("Away mode activated (min: %d°C, power off: %v)", policy.MinTemperature, policy.PowerOff)
+	if policy.PowerOff {
+		for deviceID := range e.aliases {
+			if !e.isAirConditioner(deviceID) {
+				continue // dehumidifiers and purifiers keep their own rules
+			}
+			if err := e.client.SetPower(deviceID, false); err != nil {
+				e.logf("Failed to power off %s: %v", e.alias(deviceID), err)
+			}
+		}
+	}
+}
+
+// isAirConditioner reports whether a device is an air conditioner. Devices
+// with an unknown type count as one, matching the client's default.
+func (e *Engine) isAirConditioner(deviceID string) bool {
+	deviceType := e.types[deviceID]
+	return deviceType == "" || deviceType == thinq.DeviceTypeAirConditioner
+}
+
+// Away reports whether away mode is active.
+func (e *Engine) Away() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.away
+}
+
+// AwayStatus reports whether away mode is active and the scheduled return
+// time (zero when none is set).
+func (e *Engine) AwayStatus() (bool, time.Time) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.away, e.awayUntil
+}
+
+// SetAwayUntil activates away mode until the given return time. With
+// precool set, every device is powered on at its minimum temperature one
+// hour before the return.
+func (e *Engine) SetAwayUntil(until time.Time, precool bool, policy AwayPolicy) error {
+	if !until.After(time.Now()) {
+		return fmt.Errorf("return time %s is in the past", until.Format(time.RFC3339))
+	}
+
+	e.SetAway(true, policy)
+
+	e.mu.Lock()
+	e.awayUntil = until
+	e.awayPrecool = precool
+	if precool {
+		if lead := time.Until(until.Add(-time.Hour)); lead > 0 {
+			e.awayTimers = append(e.awayTimers, time.AfterFunc(lead, e.precool))
+		}
+	}
+	e.awayTimers = append(e.awayTimers, time.AfterFunc(time.Until(until), func() {
+		e.logf("Scheduled return time reached, deactivating away mode")
+		e.SetAway(false, AwayPolicy{})
+	}))
+	e.mu.Unlock()
+
+	e.logf("Away mode scheduled until %s (precool: %v)", until.Format("2006-01-02 15:04"), precool)
+	return nil
+}
+
+// precool powers every air conditioner on at its minimum temperature ahead
+// of a scheduled return. Power-off enforcement is relaxed so the units stay
+// on.
+func (e *Engine) precool() {
+	e.logf("Pre-cooling ahead of scheduled return...")
+
+	e.mu.Lock()
+	e.awayPolicy.PowerOff = false
+	e.mu.Unlock()
+
+	for deviceID := range e.aliases {
+		if !e.isAirConditioner(deviceID) {
+			continue
+		}
+		if err := e.client.SetPower(deviceID, true); err != nil {
+			e.logf("Failed to power on %s: %v", e.alias(deviceID), err)
+			continue
+		}
+		minTemperature := e.resolver.PolicyFor(deviceID).MinTemperature
+		if err := e.client.SetTemperature(deviceID, minTemperature); err != nil {
+			e.logf("Failed to set temperature of %s: %v", e.alias(deviceID), err)
+		}
+	}
+}
+
+// checkDeviceError reports device faults (filter alarms, compressor
+// errors, ...) once per occurrence through the notifier.
+func (e *Engine) checkDeviceError(event *thinq.Event) {
+	if event.ErrorCode == "" {
+		return
+	}
+
+	e.mu.Lock()
+	previous := e.lastErrors[event.DeviceID]
+	if thinq.IsErrorClear(event.ErrorCode) {
+		delete(e.lastErrors, event.DeviceID)
+	} else {
+		e.lastErrors[event.DeviceID] = event.ErrorCode
+	}
+	e.mu.Unlock()
+
+	alias := e.alias(event.DeviceID)
+	if thinq.IsErrorClear(event.ErrorCode) {
+		if previous != "" {
+			e.logf("[%s] Error %s cleared", alias, previous)
+		}
+		return
+	}
+	if previous == event.ErrorCode {
+		return // already reported
+	}
+
+	description, known := thinq.DescribeError(event.ErrorCode)
+	if !known {
+		description = "Unknown error code"
+	}
+	e.logf("[%s] Device reported error %s: %s", alias, event.ErrorCode, description)
+
+	message := fmt.Sprintf("%s reported error %s: %s", alias, event.ErrorCode, description)
+	if err := e.notifier.Send("Device fault", message); err != nil {
+		e.logf("Failed to send notification: %v", err)
+	}
+}
+
+// DeviceErrors returns the active error code per device.
+func (e *Engine) DeviceErrors() map[string]string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	errors := make(map[string]string, len(e.lastErrors))
+	for deviceID, code := range e.lastErrors {
+		errors[deviceID] = code
+	}
+	return errors
+}
+
+// enforceAway keeps devices off while away mode requests power-off.
+func (e *Engine) enforceAway(ctx context.Context, event *thinq.Event) {
+	e.mu.Lock()
+	active := e.away && e.awayPolicy.PowerOff
+	e.mu.Unlock()
+
+	if !active || event.PowerOn == nil || !*event.PowerOn {
+		return
+	}
+
+	e.logf("[%s] Turned on while away, powering off...", e.alias(event.DeviceID))
+	_, controlSpan := tracer.Start(ctx, "rules.power_off")
+	err := e.client.SetPower(event.DeviceID, false)
+	controlSpan.End()
+	if err != nil {
+		e.logf("Failed to power off %s: %v", e.alias(event.DeviceID), err)
+	}
+}
+
+// alias returns the friendly name of a device, falling back to its ID.
+func (e *Engine) alias(deviceID string) string {
+	if alias := e.aliases[deviceID]; alias != "" {
+		return alias
+	}
+	return deviceID
+}
+
+// SetStateCache points room-temperature lookups at the shared state cache,
+// which the daemon already feeds from every event and poll, instead of the
+// engine's own last-seen map.
+func (e *Engine) SetStateCache(states *statecache.Cache) {
+	e.states = states
+}
+
+// trackRoomTemperature remembers the last reported current temperature of
+// each device, so delta rules can decide even from events that only carry
+// a target change. When a shared state cache is wired in it tracks this
+// already and the engine's own map stays unused.
+func (e *Engine) trackRoomTemperature(event *thinq.Event) {
+	if e.states != nil || event.CurrentTemperature == nil {
+		return
+	}
+	e.mu.Lock()
+	e.lastRoomTemp[event.DeviceID] = *event.CurrentTemperature
+	e.mu.Unlock()
+}
+
+// roomTemperature returns the room temperature for a decision: the one in
+// the event when present, otherwise the last known one from the shared
+// state cache (or the engine's own map when no cache is wired in).
+func (e *Engine) roomTemperature(event *thinq.Event) (float64, bool) {
+	if event.CurrentTemperature != nil {
+		return *event.CurrentTemperature, true
+	}
+	if e.states != nil {
+		state, ok := e.states.Get(event.DeviceID)
+		if !ok || state.CurrentTemperature == nil {
+			return 0, false
+		}
+		return *state.CurrentTemperature, true
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	room, ok := e.lastRoomTemp[event.DeviceID]
+	return room, ok
+}
+
+// SetLearnedProfiles installs the accepted quiet-hour windows, keyed by
+// device ID. Devices without a profile are unaffected.
+func (e *Engine) SetLearnedProfiles(windows map[string]learn.Window) {
+	e.mu.Lock()
+	e.quietHours = windows
+	e.mu.Unlock()
+}
+
+// enforceQuietHours powers a device back off when it turns on inside its
+// accepted quiet-hour window (see SetLearnedProfiles).
+func (e *Engine) enforceQuietHours(ctx context.Context, event *thinq.Event) {
+	if event.PowerOn == nil || !*event.PowerOn {
+		return
+	}
+
+	e.mu.Lock()
+	window, ok := e.quietHours[event.DeviceID]
+	e.mu.Unlock()
+	if !ok || !window.Contains(time.Now().Hour()) {
+		return
+	}
+
+	alias := e.alias(event.DeviceID)
+	if e.relaxed(event.DeviceID) {
+		e.logf("[%s] Powered on during quiet hours (%s), but enforcement is relaxed by a scene",
+			alias, window)
+		return
+	}
+
+	if !e.permitAction(event.DeviceID,
+		fmt.Sprintf("Powered on during quiet hours (%s)", window),
+		"Device on during quiet hours",
+		fmt.Sprintf("%s was turned on during its learned quiet hours (%s)", alias, window)) {
+		return
+	}
+
+	e.logf("[%s] Powered on during learned quiet hours (%s), powering off...", alias, window)
+	_, controlSpan := tracer.Start(ctx, "rules.set_power")
+	err := e.client.SetPower(event.DeviceID, false)
+	controlSpan.End()
+	if err != nil {
+		e.logf("Failed to power off: %v", err)
+	}
+}
+
+// SetEnergySavingSupport marks the devices whose profile exposes the
+// native powerSave switch. The energy_saving policy only acts on those;
+// other devices keep setpoint clamping alone.
+func (e *Engine) SetEnergySavingSupport(supported map[string]bool) {
+	e.mu.Lock()
+	e.powerSaveOK = supported
+	e.mu.Unlock()
+}
+
+// enforceEnergySaving forces the native power-save switch back on whenever
+// a device with the energy_saving policy reports itself powered on. The
+// switch is applied once per power-on, not on every status report.
+func (e *Engine) enforceEnergySaving(ctx context.Context, event *thinq.Event) {
+	if event.PowerOn == nil {
+		return
+	}
+	if !e.resolver.PolicyFor(event.DeviceID).EnergySaving {
+		return
+	}
+
+	e.mu.Lock()
+	supported := e.powerSaveOK[event.DeviceID]
+	applied := e.powerSaveOn[event.DeviceID]
+	if supported {
+		e.powerSaveOn[event.DeviceID] = *event.PowerOn
+	}
+	e.mu.Unlock()
+
+	if !supported || !*event.PowerOn || applied {
+		return
+	}
+	if e.ModeOf(event.DeviceID) != ModeEnforce {
+		return
+	}
+
+	e.logf("[%s] Powered on, enabling energy-saving mode...", e.alias(event.DeviceID))
+	_, controlSpan := tracer.Start(ctx, "rules.set_power_save")
+	err := e.client.SetPowerSave(event.DeviceID, true)
+	controlSpan.End()
+	if err != nil {
+		e.logf("Failed to enable energy saving: %v", err)
+		// Retry on the next status report
+		e.mu.Lock()
+		e.powerSaveOn[event.DeviceID] = false
+		e.mu.Unlock()
+	}
+}
+
+// trackPower records when each device was last turned on.
+func (e *Engine) trackPower(event *thinq.Event) {
+	if event.PowerOn == nil {
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	changed := false
+	if *event.PowerOn {
+		if _, ok := e.runningSince[event.DeviceID]; !ok {
+			e.runningSince[event.DeviceID] = time.Now()
+			changed = true
+		}
+	} else {
+		if _, ok := e.runningSince[event.DeviceID]; ok {
+			changed = true
+		}
+		delete(e.runningSince, event.DeviceID)
+	}
+
+	if changed {
+		e.record(history.Entry{
+			Kind:     history.KindPower,
+			DeviceID: event.DeviceID,
+			PowerOn:  event.PowerOn,
+		})
+	}
+}
+
+// enforceMinTemperature raises a target temperature that is below the
+// device's effective minimum. In AUTO mode the cool bound of the two-set
+// range plays the role of the target temperature.
+func (e *Engine) enforceMinTemperature(ctx context.Context, event *thinq.Event) {
+	var targetTemp float64
+	twoSet := false
+	switch {
+	case event.CoolTarget != nil:
+		targetTemp = *event.CoolTarget
+		twoSet = true
+	case event.TargetTemperature != nil:
+		targetTemp = *event.TargetTemperature
+	default:
+		return
+	}
+
+	policy := e.resolver.PolicyFor(event.DeviceID)
+	minTemperature := policy.MinTemperature
+
+	// A delta rule adapts the floor to the season: the target may not sit
+	// more than MaxDelta °C below the current room temperature
+	if policy.MaxDelta > 0 {
+		if room, ok := e.roomTemperature(event); ok {
+			if floor := int(math.Ceil(room)) - policy.MaxDelta; floor > minTemperature {
+				minTemperature = floor
+			}
+		}
+	}
+
+	// Away mode may raise the minimum above the regular policy
+	e.mu.Lock()
+	if e.away && e.awayPolicy.MinTemperature > minTemperature {
+		minTemperature = e.awayPolicy.MinTemperature
+	}
+	e.mu.Unlock()
+
+	if int(targetTemp) >= minTemperature {
+		e.cancelRamp(event.DeviceID)
+		return
+	}
+
+	if e.relaxed(event.DeviceID) {
+		e.logf("[%s] Temperature at %.0f°C (below minimum %d°C), but enforcement is relaxed by a scene",
+			e.alias(event.DeviceID), targetTemp, minTemperature)
+		return
+	}
+
+	e.record(history.Entry{
+		Kind:     history.KindViolation,
+		DeviceID: event.DeviceID,
+		Target:   &targetTemp,
+		Minimum:  &minTemperature,
+	})
+	trace.SpanFromContext(ctx).AddEvent("violation", trace.WithAttributes(
+		attribute.Float64("thinq.target_temperature", targetTemp),
+		attribute.Int("policy.min_temperature", minTemperature),
+	))
+
+	alias := e.alias(event.DeviceID)
+	if !e.permitAction(event.DeviceID,
+		fmt.Sprintf("Temperature at %.0f°C (below minimum %d°C)", targetTemp, minTemperature),
+		"Temperature below minimum",
+		fmt.Sprintf("%s was set to %.0f°C, below the minimum of %d°C", alias, targetTemp, minTemperature)) {
+		return
+	}
+
+	// Non-default actions skip the setpoint logic entirely
+	switch policy.Action {
+	case ActionFan:
+		e.logf("[%s] Temperature at %.0f°C (below minimum), switching to FAN mode...", alias, targetTemp)
+		_, controlSpan := tracer.Start(ctx, "rules.set_job_mode")
+		err := e.client.SetJobMode(event.DeviceID, "FAN")
+		controlSpan.End()
+		if err != nil {
+			e.logf("Failed to switch to FAN mode: %v", err)
+		} else {
+			e.record(history.Entry{
+				Kind:     history.KindCorrection,
+				DeviceID: event.DeviceID,
+				Target:   &targetTemp,
+				Minimum:  &minTemperature,
+			})
+			e.noteCorrection(event.DeviceID)
+		}
+		return
+	case ActionPowerOff:
+		e.logf("[%s] Temperature at %.0f°C (below minimum), powering off...", alias, targetTemp)
+		_, controlSpan := tracer.Start(ctx, "rules.set_power")
+		err := e.client.SetPower(event.DeviceID, false)
+		controlSpan.End()
+		if err != nil {
+			e.logf("Failed to power off: %v", err)
+		} else {
+			e.record(history.Entry{
+				Kind:     history.KindCorrection,
+				DeviceID: event.DeviceID,
+				Target:   &targetTemp,
+				Minimum:  &minTemperature,
+			})
+			e.noteCorrection(event.DeviceID)
+		}
+		return
+	}
+
+	// Ramping strategy: approach the minimum 1°C at a time
+	if policy.RampMinutes > 0 && !twoSet {
+		e.handleRamp(event.DeviceID, int(targetTemp), minTemperature, policy.RampMinutes)
+		return
+	}
+
+	e.logf("[%s] Temperature at %.0f°C (below minimum), adjusting to %d°C...",
+		alias, targetTemp, minTemperature)
+
+	_, controlSpan := tracer.Start(ctx, "rules.set_temperature")
+	var err error
+	if twoSet {
+		err = e.client.SetTwoSetTemperature(event.DeviceID, minTemperature, 0)
+	} else {
+		err = e.client.SetTemperature(event.DeviceID, minTemperature)
+	}
+	controlSpan.End()
+	if err != nil {
+		e.logf("Failed to adjust temperature: %v", err)
+	} else {
+		e.logf("Temperature adjusted successfully!")
+		e.record(history.Entry{
+			Kind:     history.KindCorrection,
+			DeviceID: event.DeviceID,
+			Target:   &targetTemp,
+			Minimum:  &minTemperature,
+		})
+		e.noteCorrection(event.DeviceID)
+	}
+}
+
+// enforceMaxRunning powers off the longest-running unit when a device
+// turning on exceeds the household-wide or zone-level running cap.
+func (e *Engine) enforceMaxRunning(ctx context.Context, event *thinq.Event) {
+	if event.PowerOn == nil || !*event.PowerOn {
+		return
+	}
+
+	// Zone-level cap (counts only devices in the same zone)
+	zone := e.resolver.ZoneOf(event.DeviceID)
+	if zoneCap := e.resolver.PolicyFor(event.DeviceID).MaxRunning; zoneCap > 0 && zone != "" {
+		zoneDevices := make(map[string]bool)
+		for _, deviceID := range e.resolver.ZoneDevices(zone) {
+			zoneDevices[deviceID] = true
+		}
+		e.applyRunningCap(ctx, zoneCap, zone, func(deviceID string) bool { return zoneDevices[deviceID] })
+	}
+
+	// Household-wide cap from the global defaults
+	if cap := e.resolver.Defaults().MaxRunning; cap > 0 {
+		e.applyRunningCap(ctx, cap, "household", func(string) bool { return true })
+	}
+}
+
+// applyRunningCap powers off the longest-running devices matching the
+// filter until at most cap remain running.
+func (e *Engine) applyRunningCap(ctx context.Context, cap int, scope string, matches func(deviceID string) bool) {
+	for {
+		victim, running := e.pickVictim(matches)
+		if running <= cap {
+			return
+		}
+
+		e.logf("[%s] %d unit(s) running (cap: %d), powering off longest-running: %s",
+			scope, running, cap, e.alias(victim))
+
+		_, controlSpan := tracer.Start(ctx, "rules.power_off")
+		err := e.client.SetPower(victim, false)
+		controlSpan.End()
+		if err != nil {
+			e.logf("Failed to power off %s: %v", e.alias(victim), err)
+			return
+		}
+
+		e.mu.Lock()
+		delete(e.runningSince, victim)
+		e.mu.Unlock()
+	}
+}
+
+// pickVictim returns the matching device running the longest and how many
+// matching devices are currently running.
+func (e *Engine) pickVictim(matches func(deviceID string) bool) (string, int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var victim string
+	var oldest time.Time
+	running := 0
+	for deviceID, since := range e.runningSince {
+		if !matches(deviceID) {
+			continue
+		}
+		running++
+		if victim == "" || since.Before(oldest) {
+			victim = deviceID
+			oldest = since
+		}
+	}
+
+	return victim, running
+}
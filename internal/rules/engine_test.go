@@ -0,0 +1,181 @@
+package rules
+
+import (
+	"controle-arcondicionado/internal/config"
+	"controle-arcondicionado/internal/notify"
+	"controle-arcondicionado/internal/thinq"
+	"testing"
+	"time"
+)
+
+// call is one control command captured by the fake controller.
+type call struct {
+	method   string
+	deviceID string
+	value    int
+	on       bool
+}
+
+// fakeController records every control command instead of talking to LG.
+type fakeController struct {
+	calls []call
+}
+
+func (f *fakeController) SetTemperature(deviceID string, temperature int) error {
+	f.calls = append(f.calls, call{method: "SetTemperature", deviceID: deviceID, value: temperature})
+	return nil
+}
+
+func (f *fakeController) SetPower(deviceID string, on bool) error {
+	f.calls = append(f.calls, call{method: "SetPower", deviceID: deviceID, on: on})
+	return nil
+}
+
+func (f *fakeController) SetJobMode(deviceID string, mode string) error {
+	f.calls = append(f.calls, call{method: "SetJobMode", deviceID: deviceID})
+	return nil
+}
+
+func (f *fakeController) SetTwoSetTemperature(deviceID string, coolTarget, heatTarget int) error {
+	f.calls = append(f.calls, call{method: "SetTwoSetTemperature", deviceID: deviceID, value: coolTarget})
+	return nil
+}
+
+func (f *fakeController) SetWindStrength(deviceID string, strength string) error {
+	f.calls = append(f.calls, call{method: "SetWindStrength", deviceID: deviceID})
+	return nil
+}
+
+func (f *fakeController) SetPowerSave(deviceID string, on bool) error {
+	f.calls = append(f.calls, call{method: "SetPowerSave", deviceID: deviceID, on: on})
+	return nil
+}
+
+func (f *fakeController) SetTargetHumidity(deviceID string, humidity int) error {
+	f.calls = append(f.calls, call{method: "SetTargetHumidity", deviceID: deviceID, value: humidity})
+	return nil
+}
+
+// newTestEngine wires an engine around the fake controller and a log-only
+// notifier, with one air conditioner per given alias.
+func newTestEngine(t *testing.T, cfg *config.Config, aliases ...string) (*Engine, *fakeController) {
+	t.Helper()
+	devices := make([]thinq.Device, len(aliases))
+	for i, alias := range aliases {
+		devices[i] = thinq.Device{
+			DeviceID:   alias,
+			Alias:      alias,
+			DeviceType: thinq.DeviceTypeAirConditioner,
+		}
+	}
+	client := &fakeController{}
+	resolver := NewResolver(cfg, devices)
+	engine := NewEngine(client, resolver, devices, &notify.LogNotifier{Logf: t.Logf}, t.Logf)
+	return engine, client
+}
+
+func floatPtr(value float64) *float64 { return &value }
+func boolPtr(value bool) *bool        { return &value }
+
+func TestEnforceMinTemperatureClampsBelowMinimum(t *testing.T) {
+	engine, client := newTestEngine(t, &config.Config{MinTemperature: 21}, "Sala")
+
+	engine.HandleEvent(&thinq.Event{
+		DeviceID:          "Sala",
+		PushType:          "DEVICE_STATUS",
+		TargetTemperature: floatPtr(18),
+	})
+
+	if len(client.calls) != 1 {
+		t.Fatalf("got %d control call(s), want 1: %+v", len(client.calls), client.calls)
+	}
+	got := client.calls[0]
+	if got.method != "SetTemperature" || got.deviceID != "Sala" || got.value != 21 {
+		t.Errorf("got %+v, want SetTemperature(Sala, 21)", got)
+	}
+}
+
+func TestEnforceMinTemperatureLeavesCompliantTargetAlone(t *testing.T) {
+	engine, client := newTestEngine(t, &config.Config{MinTemperature: 21}, "Sala")
+
+	engine.HandleEvent(&thinq.Event{
+		DeviceID:          "Sala",
+		PushType:          "DEVICE_STATUS",
+		TargetTemperature: floatPtr(23),
+	})
+
+	if len(client.calls) != 0 {
+		t.Errorf("got %d control call(s), want none: %+v", len(client.calls), client.calls)
+	}
+}
+
+func TestEnforceMinTemperatureUsesTwoSetPathInAutoMode(t *testing.T) {
+	engine, client := newTestEngine(t, &config.Config{MinTemperature: 21}, "Sala")
+
+	engine.HandleEvent(&thinq.Event{
+		DeviceID:   "Sala",
+		PushType:   "DEVICE_STATUS",
+		CoolTarget: floatPtr(18),
+	})
+
+	if len(client.calls) != 1 {
+		t.Fatalf("got %d control call(s), want 1: %+v", len(client.calls), client.calls)
+	}
+	got := client.calls[0]
+	if got.method != "SetTwoSetTemperature" || got.deviceID != "Sala" || got.value != 21 {
+		t.Errorf("got %+v, want SetTwoSetTemperature(Sala, 21, 0)", got)
+	}
+}
+
+func TestMaxDeltaRaisesFloorAboveMinimum(t *testing.T) {
+	engine, client := newTestEngine(t, &config.Config{MinTemperature: 18, MaxDelta: 6}, "Sala")
+
+	// Room at 30°C with a 6°C delta cap: the floor becomes 24°C even
+	// though the target of 21°C satisfies the static minimum
+	engine.HandleEvent(&thinq.Event{
+		DeviceID:           "Sala",
+		PushType:           "DEVICE_STATUS",
+		CurrentTemperature: floatPtr(30),
+		TargetTemperature:  floatPtr(21),
+	})
+
+	if len(client.calls) != 1 {
+		t.Fatalf("got %d control call(s), want 1: %+v", len(client.calls), client.calls)
+	}
+	got := client.calls[0]
+	if got.method != "SetTemperature" || got.value != 24 {
+		t.Errorf("got %+v, want SetTemperature(Sala, 24)", got)
+	}
+}
+
+func TestRunningCapPowersOffLongestRunning(t *testing.T) {
+	engine, client := newTestEngine(t, &config.Config{MinTemperature: 21, MaxRunning: 1}, "Sala", "Quarto")
+
+	engine.HandleEvent(&thinq.Event{
+		DeviceID: "Sala",
+		PushType: "DEVICE_STATUS",
+		PowerOn:  boolPtr(true),
+	})
+	if len(client.calls) != 0 {
+		t.Fatalf("first power-on triggered %d control call(s): %+v", len(client.calls), client.calls)
+	}
+
+	// Make the first unit unambiguously the longest-running one
+	engine.mu.Lock()
+	engine.runningSince["Sala"] = time.Now().Add(-time.Hour)
+	engine.mu.Unlock()
+
+	engine.HandleEvent(&thinq.Event{
+		DeviceID: "Quarto",
+		PushType: "DEVICE_STATUS",
+		PowerOn:  boolPtr(true),
+	})
+
+	if len(client.calls) != 1 {
+		t.Fatalf("got %d control call(s), want 1: %+v", len(client.calls), client.calls)
+	}
+	got := client.calls[0]
+	if got.method != "SetPower" || got.deviceID != "Sala" || got.on {
+		t.Errorf("got %+v, want SetPower(Sala, false)", got)
+	}
+}
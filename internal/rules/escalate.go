@@ -0,0 +1,85 @@
+package rules
+
+import (
+	"controle-arcondicionado/internal/history"
+	"controle-arcondicionado/internal/notify"
+	"fmt"
+	"time"
+)
+
+// escalationWindow is the sliding window over which corrections count
+// toward an escalation, and the cooldown between escalations per device.
+const escalationWindow = time.Hour
+
+// SetEscalation enables the repeated-violation rule: when a device is
+// corrected more than `after` times within an hour, a high-priority
+// notification goes out and the streak is recorded in the history. With
+// lock set, the device is also switched to notify mode so further changes
+// are reported to a human instead of silently corrected again.
+func (e *Engine) SetEscalation(after int, lock bool) {
+	e.mu.Lock()
+	e.escalateAfter = after
+	e.escalateLock = lock
+	e.mu.Unlock()
+}
+
+// noteCorrection counts one applied correction, feeding both the per-device
+// statistics and the escalation rule.
+func (e *Engine) noteCorrection(deviceID string) {
+	if e.states != nil {
+		e.states.NoteCorrection(deviceID)
+	}
+
+	now := time.Now()
+
+	e.mu.Lock()
+	if e.escalateAfter <= 0 {
+		e.mu.Unlock()
+		return
+	}
+	recent := e.corrections[deviceID][:0]
+	for _, at := range e.corrections[deviceID] {
+		if now.Sub(at) <= escalationWindow {
+			recent = append(recent, at)
+		}
+	}
+	recent = append(recent, now)
+	e.corrections[deviceID] = recent
+
+	count := len(recent)
+	trigger := count > e.escalateAfter && now.Sub(e.lastEscalation[deviceID]) > escalationWindow
+	if trigger {
+		e.lastEscalation[deviceID] = now
+	}
+	lock := e.escalateLock
+	e.mu.Unlock()
+
+	if !trigger {
+		return
+	}
+
+	alias := e.alias(deviceID)
+	e.logf("[%s] Escalation: corrected %d times in the last hour", alias, count)
+	e.record(history.Entry{
+		Kind:     history.KindEscalation,
+		DeviceID: deviceID,
+		Counts:   map[string]int{history.KindCorrection: count},
+	})
+
+	title := "Repeated policy violations"
+	message := fmt.Sprintf("%s was corrected %d times in the last hour", alias, count)
+	var err error
+	if urgent, ok := e.notifier.(notify.UrgentSender); ok {
+		err = urgent.SendUrgent(title, message)
+	} else {
+		err = e.notifier.Send(title, message)
+	}
+	if err != nil {
+		e.logf("Failed to send escalation notification: %v", err)
+	}
+
+	if lock {
+		e.logf("[%s] Locking to notify mode after repeated violations", alias)
+		e.SetMode(deviceID, ModeNotify)
+	}
+}
@@ -0,0 +1,74 @@
+package rules
+
+import (
+	"context"
+	"controle-arcondicionado/internal/thinq"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// nearTargetMargin is how close (in °C) the room must be to its target
+// before DRY mode is considered instead of further cooling.
+const nearTargetMargin = 1.0
+
+// dryCooldown prevents flapping between COOL and DRY on every event.
+const dryCooldown = 30 * time.Minute
+
+// jobModeDry is LG's dehumidify job mode.
+const jobModeDry = "AIR_DRY"
+
+// enforceDryMode switches a cooling unit into DRY mode when relative
+// humidity exceeds the policy threshold while the room is already near its
+// target temperature: removing moisture is then cheaper than deeper cooling.
+func (e *Engine) enforceDryMode(ctx context.Context, event *thinq.Event) {
+	threshold := e.resolver.PolicyFor(event.DeviceID).DryHumidity
+	if threshold <= 0 || event.Humidity == nil {
+		return
+	}
+	// Only act on a complete picture: humidity, both temperatures and a
+	// unit that is actually cooling
+	if event.CurrentTemperature == nil || event.TargetTemperature == nil || event.JobMode != jobModeCool {
+		return
+	}
+	if event.PowerOn != nil && !*event.PowerOn {
+		return
+	}
+
+	humidity := *event.Humidity
+	if humidity < float64(threshold) {
+		return
+	}
+	if *event.CurrentTemperature > *event.TargetTemperature+nearTargetMargin {
+		return // still far from target, let it cool
+	}
+
+	e.mu.Lock()
+	if time.Since(e.lastDrySwitch[event.DeviceID]) < dryCooldown {
+		e.mu.Unlock()
+		return
+	}
+	e.lastDrySwitch[event.DeviceID] = time.Now()
+	e.mu.Unlock()
+
+	trace.SpanFromContext(ctx).AddEvent("dry_mode", trace.WithAttributes(
+		attribute.Float64("thinq.humidity", humidity),
+		attribute.Int("policy.dry_humidity", threshold),
+	))
+
+	alias := e.alias(event.DeviceID)
+	if !e.permitAction(event.DeviceID,
+		fmt.Sprintf("Humidity at %.0f%% (above %d%%) near target", humidity, threshold),
+		"High humidity",
+		fmt.Sprintf("%s is at %.0f%% humidity near its target; DRY mode would save energy", alias, humidity)) {
+		return
+	}
+
+	e.logf("[%s] Humidity at %.0f%% (above %d%%) near target, switching to DRY mode...",
+		alias, humidity, threshold)
+	if err := e.client.SetJobMode(event.DeviceID, jobModeDry); err != nil {
+		e.logf("Failed to switch %s to DRY mode: %v", alias, err)
+	}
+}
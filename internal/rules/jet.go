@@ -0,0 +1,62 @@
+package rules
+
+import (
+	"controle-arcondicionado/internal/thinq"
+	"fmt"
+	"time"
+)
+
+// jetLimit is how long jet (turbo) mode may stay active before the engine
+// switches the device back to regular cooling. Jet mode runs the compressor
+// flat out and defeats the purpose of the energy saver when left on.
+const jetLimit = 15 * time.Minute
+
+const (
+	jobModeJet  = "JET"
+	jobModeCool = "COOL"
+)
+
+// enforceJetLimit starts a countdown when a device enters jet mode and
+// cancels it when the device leaves jet mode or powers off. When the
+// countdown expires the device is switched back to regular cooling.
+func (e *Engine) enforceJetLimit(event *thinq.Event) {
+	deviceID := event.DeviceID
+	poweredOff := event.PowerOn != nil && !*event.PowerOn
+
+	e.mu.Lock()
+	timer, counting := e.jetTimers[deviceID]
+	switch {
+	case event.JobMode == jobModeJet && !counting && !poweredOff:
+		e.jetTimers[deviceID] = time.AfterFunc(jetLimit, func() { e.jetExpired(deviceID) })
+		e.mu.Unlock()
+		e.logf("[%s] Jet mode activated, allowing it for %s", e.alias(deviceID), jetLimit)
+	case counting && (poweredOff || (event.JobMode != "" && event.JobMode != jobModeJet)):
+		timer.Stop()
+		delete(e.jetTimers, deviceID)
+		e.mu.Unlock()
+		e.logf("[%s] Jet mode ended, countdown cancelled", e.alias(deviceID))
+	default:
+		e.mu.Unlock()
+	}
+}
+
+// jetExpired runs when a device has been in jet mode for the full limit,
+// acting according to the device's enforcement mode.
+func (e *Engine) jetExpired(deviceID string) {
+	e.mu.Lock()
+	delete(e.jetTimers, deviceID)
+	e.mu.Unlock()
+
+	alias := e.alias(deviceID)
+	if !e.permitAction(deviceID,
+		fmt.Sprintf("Jet mode active for %s", jetLimit),
+		"Jet mode still active",
+		fmt.Sprintf("%s has been in jet mode for %s", alias, jetLimit)) {
+		return
+	}
+
+	e.logf("[%s] Jet mode active for %s, switching back to cooling...", alias, jetLimit)
+	if err := e.client.SetJobMode(deviceID, jobModeCool); err != nil {
+		e.logf("Failed to end jet mode on %s: %v", alias, err)
+	}
+}
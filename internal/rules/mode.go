@@ -0,0 +1,24 @@
+package rules
+
+import "fmt"
+
+// Mode controls how the engine reacts when a device violates its policy.
+type Mode string
+
+const (
+	// ModeEnforce corrects the violation with a control call (default).
+	ModeEnforce Mode = "enforce"
+	// ModeNotify sends a notification but makes no control call.
+	ModeNotify Mode = "notify"
+	// ModeLog only records the violation in the daemon log.
+	ModeLog Mode = "log"
+)
+
+// ParseMode validates a mode string.
+func ParseMode(s string) (Mode, error) {
+	switch Mode(s) {
+	case ModeEnforce, ModeNotify, ModeLog:
+		return Mode(s), nil
+	}
+	return "", fmt.Errorf("invalid mode %q (expected enforce, notify or log)", s)
+}
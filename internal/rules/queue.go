@@ -0,0 +1,178 @@
+package rules
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	// retryBase is the delay before the first retry of a failed command;
+	// it doubles on every attempt up to retryMaxDelay.
+	retryBase = 30 * time.Second
+
+	// retryMaxDelay caps the backoff between attempts.
+	retryMaxDelay = 5 * time.Minute
+
+	// retryWindow bounds how long a command keeps being retried before
+	// it is dropped.
+	retryWindow = 30 * time.Minute
+)
+
+// pendingCommand is a failed control call waiting for a retry.
+type pendingCommand struct {
+	describe    string
+	run         func() error
+	firstFailed time.Time
+	attempt     int
+	timer       *time.Timer
+}
+
+// ControlQueue wraps a Controller and retries failed control calls with
+// exponential backoff while the LG API is unreachable. Commands are keyed
+// by device and resource, so a newer command for the same resource
+// supersedes a queued one instead of piling up. A command that keeps
+// failing past the retry window is dropped with a log message.
+type ControlQueue struct {
+	mu      sync.Mutex
+	client  Controller
+	pending map[string]*pendingCommand // key: deviceID + resource
+	logf    func(format string, args ...interface{})
+}
+
+// NewControlQueue wraps the given controller with retry queueing.
+func NewControlQueue(client Controller, logf func(format string, args ...interface{})) *ControlQueue {
+	return &ControlQueue{
+		client:  client,
+		pending: make(map[string]*pendingCommand),
+		logf:    logf,
+	}
+}
+
+// SetTemperature implements Controller with retry queueing.
+func (q *ControlQueue) SetTemperature(deviceID string, temperature int) error {
+	return q.submit(deviceID, "temperature",
+		fmt.Sprintf("set temperature of %s to %d°C", deviceID, temperature),
+		func() error { return q.client.SetTemperature(deviceID, temperature) })
+}
+
+// SetPower implements Controller with retry queueing.
+func (q *ControlQueue) SetPower(deviceID string, on bool) error {
+	return q.submit(deviceID, "power",
+		fmt.Sprintf("set power of %s to %v", deviceID, on),
+		func() error { return q.client.SetPower(deviceID, on) })
+}
+
+// SetJobMode implements Controller with retry queueing.
+func (q *ControlQueue) SetJobMode(deviceID string, mode string) error {
+	return q.submit(deviceID, "jobMode",
+		fmt.Sprintf("set job mode of %s to %s", deviceID, mode),
+		func() error { return q.client.SetJobMode(deviceID, mode) })
+}
+
+// SetTwoSetTemperature implements Controller with retry queueing. It shares
+// the "temperature" key with SetTemperature since both target the same
+// resource on the device.
+func (q *ControlQueue) SetTwoSetTemperature(deviceID string, coolTarget, heatTarget int) error {
+	return q.submit(deviceID, "temperature",
+		fmt.Sprintf("set two-set temperature of %s to %d/%d°C", deviceID, coolTarget, heatTarget),
+		func() error { return q.client.SetTwoSetTemperature(deviceID, coolTarget, heatTarget) })
+}
+
+// SetWindStrength implements Controller with retry queueing.
+func (q *ControlQueue) SetWindStrength(deviceID string, strength string) error {
+	return q.submit(deviceID, "windStrength",
+		fmt.Sprintf("set wind strength of %s to %s", deviceID, strength),
+		func() error { return q.client.SetWindStrength(deviceID, strength) })
+}
+
+// SetTargetHumidity implements Controller with retry queueing.
+func (q *ControlQueue) SetTargetHumidity(deviceID string, humidity int) error {
+	return q.submit(deviceID, "humidity",
+		fmt.Sprintf("set target humidity of %s to %d%%", deviceID, humidity),
+		func() error { return q.client.SetTargetHumidity(deviceID, humidity) })
+}
+
+func (q *ControlQueue) SetPowerSave(deviceID string, on bool) error {
+	return q.submit(deviceID, "powerSave",
+		fmt.Sprintf("set power save of %s to %v", deviceID, on),
+		func() error { return q.client.SetPowerSave(deviceID, on) })
+}
+
+// submit attempts the command immediately. On failure it queues a retry
+// (superseding any queued command for the same device/resource) and returns
+// the original error, so callers log the failure as before.
+func (q *ControlQueue) submit(deviceID, resource, describe string, run func() error) error {
+	err := run()
+	if err == nil {
+		q.drop(deviceID + "/" + resource)
+		return nil
+	}
+
+	key := deviceID + "/" + resource
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if old, ok := q.pending[key]; ok {
+		old.timer.Stop()
+		q.logf("Control queue: superseding queued command (%s)", old.describe)
+	}
+
+	cmd := &pendingCommand{
+		describe:    describe,
+		run:         run,
+		firstFailed: time.Now(),
+	}
+	q.pending[key] = cmd
+	cmd.timer = time.AfterFunc(retryBase, func() { q.retry(key, cmd) })
+	q.logf("Control queue: will retry in %s: %s", retryBase, describe)
+
+	return err
+}
+
+// retry re-runs a queued command, rescheduling with doubled delay on
+// failure until the retry window is exhausted.
+func (q *ControlQueue) retry(key string, cmd *pendingCommand) {
+	q.mu.Lock()
+	if q.pending[key] != cmd {
+		// Superseded or already delivered meanwhile
+		q.mu.Unlock()
+		return
+	}
+	q.mu.Unlock()
+
+	if err := cmd.run(); err == nil {
+		q.logf("Control queue: delivered after %d retry(ies): %s", cmd.attempt+1, cmd.describe)
+		q.drop(key)
+		return
+	} else if time.Since(cmd.firstFailed) > retryWindow {
+		q.logf("Control queue: dropping after %s: %s (last error: %v)",
+			time.Since(cmd.firstFailed).Round(time.Second), cmd.describe, err)
+		q.drop(key)
+		return
+	}
+
+	cmd.attempt++
+	delay := retryBase << cmd.attempt
+	if delay > retryMaxDelay {
+		delay = retryMaxDelay
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.pending[key] != cmd {
+		return
+	}
+	cmd.timer = time.AfterFunc(delay, func() { q.retry(key, cmd) })
+	q.logf("Control queue: still failing, next retry in %s: %s", delay, cmd.describe)
+}
+
+// drop removes a queued command and cancels its retry timer.
+func (q *ControlQueue) drop(key string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if cmd, ok := q.pending[key]; ok {
+		cmd.timer.Stop()
+		delete(q.pending, key)
+	}
+}
@@ -0,0 +1,87 @@
+package rules
+
+import (
+	"controle-arcondicionado/internal/history"
+	"time"
+)
+
+// rampState tracks an in-progress gradual correction toward the minimum.
+type rampState struct {
+	timer       *time.Timer
+	lastSet     int // last temperature we commanded
+	minimum     int
+	stepMinutes int
+}
+
+// handleRamp corrects a violation by raising the target 1°C per step
+// instead of clamping straight to the minimum, which is less noticeable
+// to occupants.
+func (e *Engine) handleRamp(deviceID string, target, minimum, stepMinutes int) {
+	e.mu.Lock()
+	if state, active := e.ramps[deviceID]; active {
+		if target >= state.lastSet {
+			// Echo of our own step (or the user raised it); the timer
+			// will continue the ramp
+			e.mu.Unlock()
+			return
+		}
+		// The user pushed the target lower again: restart from there
+		state.timer.Stop()
+		delete(e.ramps, deviceID)
+	}
+	e.mu.Unlock()
+
+	e.logf("[%s] Ramping from %d°C to %d°C, +1°C every %d minute(s)",
+		e.alias(deviceID), target, minimum, stepMinutes)
+	e.rampTo(deviceID, target+1, minimum, stepMinutes)
+}
+
+// rampTo commands one ramp step and schedules the next when not done.
+func (e *Engine) rampTo(deviceID string, next, minimum, stepMinutes int) {
+	if next > minimum {
+		next = minimum
+	}
+
+	if err := e.client.SetTemperature(deviceID, next); err != nil {
+		e.logf("Failed to ramp temperature of %s: %v", e.alias(deviceID), err)
+		e.mu.Lock()
+		delete(e.ramps, deviceID)
+		e.mu.Unlock()
+		return
+	}
+
+	target := float64(next)
+	e.record(history.Entry{
+		Kind:     history.KindCorrection,
+		DeviceID: deviceID,
+		Target:   &target,
+		Minimum:  &minimum,
+	})
+	e.noteCorrection(deviceID)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if next >= minimum {
+		e.logf("[%s] Ramp complete at %d°C", e.alias(deviceID), next)
+		delete(e.ramps, deviceID)
+		return
+	}
+
+	state := &rampState{lastSet: next, minimum: minimum, stepMinutes: stepMinutes}
+	state.timer = time.AfterFunc(time.Duration(stepMinutes)*time.Minute, func() {
+		e.rampTo(deviceID, next+1, minimum, stepMinutes)
+	})
+	e.ramps[deviceID] = state
+}
+
+// cancelRamp stops an in-progress ramp, e.g. when the device is back
+// above the minimum on its own.
+func (e *Engine) cancelRamp(deviceID string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if state, active := e.ramps[deviceID]; active {
+		state.timer.Stop()
+		delete(e.ramps, deviceID)
+	}
+}
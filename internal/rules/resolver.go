@@ -0,0 +1,155 @@
+// Package rules resolves and enforces the energy-saving policies that
+// govern each device: the global defaults, zone-level policies and
+// per-device overrides from the config file.
+package rules
+
+import (
+	"controle-arcondicionado/internal/config"
+	"controle-arcondicionado/internal/thinq"
+)
+
+// Policy is the effective, fully-resolved policy for a single device.
+type Policy struct {
+	MinTemperature int
+	MaxRunning     int // 0 = unlimited
+	Mode           Mode
+	RampMinutes    int // minutes between 1°C ramp steps (0 = clamp immediately)
+	DryHumidity    int // humidity % above which DRY mode is preferred (0 = off)
+	MinHumidity    int // dehumidifier target humidity floor in % (0 = off)
+	AutoOffMinutes int // power the device off after this many minutes running (0 = off)
+	MaxDelta       int // max °C the target may sit below the room temperature (0 = off)
+	Action         Action
+	EnergySaving   bool // force the native powerSave switch on while running
+}
+
+// Resolver computes effective per-device policies by layering the global
+// defaults, the device's zone policy and its device-level override.
+// Zones and overrides reference devices by alias.
+type Resolver struct {
+	defaults  Policy
+	zoneByID  map[string]*config.Zone
+	overrides map[string]*config.DeviceOverride
+	aliasToID map[string]string
+}
+
+// NewResolver builds a resolver for the given configuration and device list.
+func NewResolver(cfg *config.Config, devices []thinq.Device) *Resolver {
+	r := &Resolver{
+		defaults: Policy{
+			MinTemperature: cfg.MinTemperature,
+			MaxRunning:     cfg.MaxRunning,
+			Mode:           ModeEnforce,
+			DryHumidity:    cfg.DryHumidity,
+			MinHumidity:    cfg.MinHumidity,
+			AutoOffMinutes: cfg.AutoOffMinutes,
+			MaxDelta:       cfg.MaxDelta,
+			Action:         ActionAdjust,
+			EnergySaving:   cfg.EnergySaving,
+		},
+		zoneByID:  make(map[string]*config.Zone),
+		overrides: make(map[string]*config.DeviceOverride),
+		aliasToID: make(map[string]string),
+	}
+
+	idByAlias := make(map[string]string)
+	for _, device := range devices {
+		idByAlias[device.Alias] = device.DeviceID
+		r.aliasToID[device.Alias] = device.DeviceID
+	}
+
+	for i := range cfg.Zones {
+		zone := &cfg.Zones[i]
+		for _, alias := range zone.Devices {
+			if deviceID, ok := idByAlias[alias]; ok {
+				r.zoneByID[deviceID] = zone
+			}
+		}
+	}
+
+	for i := range cfg.DeviceOverrides {
+		override := &cfg.DeviceOverrides[i]
+		if deviceID, ok := idByAlias[override.Name]; ok {
+			r.overrides[deviceID] = override
+		}
+	}
+
+	return r
+}
+
+// Defaults returns the global default policy.
+func (r *Resolver) Defaults() Policy {
+	return r.defaults
+}
+
+// PolicyFor returns the effective policy for a device: defaults overlaid
+// with its zone policy, overlaid with its device override.
+func (r *Resolver) PolicyFor(deviceID string) Policy {
+	policy := r.defaults
+
+	if zone, ok := r.zoneByID[deviceID]; ok {
+		applyPolicy(&policy, zone.Policy)
+	}
+	if override, ok := r.overrides[deviceID]; ok {
+		applyPolicy(&policy, override.Policy)
+	}
+
+	return policy
+}
+
+// ZoneOf returns the name of the zone the device belongs to, or "" when
+// the device is not part of any configured zone.
+func (r *Resolver) ZoneOf(deviceID string) string {
+	if zone, ok := r.zoneByID[deviceID]; ok {
+		return zone.Name
+	}
+	return ""
+}
+
+// ZoneDevices returns the deviceIDs of every known device in the named zone.
+func (r *Resolver) ZoneDevices(name string) []string {
+	var deviceIDs []string
+	for deviceID, zone := range r.zoneByID {
+		if zone.Name == name {
+			deviceIDs = append(deviceIDs, deviceID)
+		}
+	}
+	return deviceIDs
+}
+
+// applyPolicy overlays the non-nil fields of the config policy.
+func applyPolicy(policy *Policy, overlay config.Policy) {
+	if overlay.MinTemperature != nil {
+		policy.MinTemperature = *overlay.MinTemperature
+	}
+	if overlay.MaxRunning != nil {
+		policy.MaxRunning = *overlay.MaxRunning
+	}
+	if overlay.Mode != "" {
+		if mode, err := ParseMode(overlay.Mode); err == nil {
+			policy.Mode = mode
+		}
+	}
+	if overlay.RampMinutes != nil {
+		policy.RampMinutes = *overlay.RampMinutes
+	}
+	if overlay.DryHumidity != nil {
+		policy.DryHumidity = *overlay.DryHumidity
+	}
+	if overlay.MinHumidity != nil {
+		policy.MinHumidity = *overlay.MinHumidity
+	}
+	if overlay.AutoOffMinutes != nil {
+		policy.AutoOffMinutes = *overlay.AutoOffMinutes
+	}
+	if overlay.MaxDelta != nil {
+		policy.MaxDelta = *overlay.MaxDelta
+	}
+	if overlay.Action != "" {
+		if action, err := ParseAction(overlay.Action); err == nil {
+			policy.Action = action
+		}
+	}
+	if overlay.EnergySaving != nil {
+		policy.EnergySaving = *overlay.EnergySaving
+	}
+}
@@ -0,0 +1,120 @@
+package rules
+
+import (
+	"controle-arcondicionado/internal/config"
+	"controle-arcondicionado/internal/thinq"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SetScenes makes the named scenes from the config activatable.
+func (e *Engine) SetScenes(scenes map[string]config.Scene) {
+	e.mu.Lock()
+	e.scenes = scenes
+	e.mu.Unlock()
+}
+
+// Scenes returns the configured scene names, sorted.
+func (e *Engine) Scenes() []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	names := make([]string, 0, len(e.scenes))
+	for name := range e.scenes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ApplyScene activates a named scene: every step is applied to its device
+// (or to every device of its zone), and when the scene sets relax_minutes
+// enforcement is suspended on the touched devices for that long, so the
+// scene's own values are not immediately corrected.
+func (e *Engine) ApplyScene(name string) error {
+	e.mu.Lock()
+	scene, ok := e.scenes[name]
+	e.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("unknown scene %q", name)
+	}
+
+	e.logf("Activating scene %q...", name)
+	failures := 0
+	for _, step := range scene.Devices {
+		for _, deviceID := range e.sceneTargets(step.Name) {
+			if err := e.applySceneStep(deviceID, step); err != nil {
+				e.logf("[%s] Scene %q failed: %v", e.alias(deviceID), name, err)
+				failures++
+				continue
+			}
+			if scene.RelaxMinutes > 0 {
+				e.relax(deviceID, time.Duration(scene.RelaxMinutes)*time.Minute)
+			}
+		}
+	}
+
+	if failures > 0 {
+		return fmt.Errorf("scene %q finished with %d failure(s)", name, failures)
+	}
+	e.logf("Scene %q activated", name)
+	return nil
+}
+
+// sceneTargets resolves a step name to device IDs: a zone name covers all
+// its devices, otherwise the name is resolved as alias, alias prefix or
+// device ID.
+func (e *Engine) sceneTargets(name string) []string {
+	if devices := e.resolver.ZoneDevices(name); len(devices) > 0 {
+		return devices
+	}
+	deviceID, err := thinq.ResolveDevice(e.devices, name)
+	if err != nil {
+		e.logf("Scene step: %v", err)
+		return nil
+	}
+	return []string{deviceID}
+}
+
+// applySceneStep sends the step's settings to the device, stopping at the
+// first failure. Power is applied first so the other settings land on a
+// unit that is awake.
+func (e *Engine) applySceneStep(deviceID string, step config.SceneStep) error {
+	if step.Power != nil {
+		if err := e.client.SetPower(deviceID, *step.Power); err != nil {
+			return err
+		}
+	}
+	if step.Mode != "" {
+		if err := e.client.SetJobMode(deviceID, strings.ToUpper(step.Mode)); err != nil {
+			return err
+		}
+	}
+	if step.Temperature != nil {
+		if err := e.client.SetTemperature(deviceID, *step.Temperature); err != nil {
+			return err
+		}
+	}
+	if step.Fan != "" {
+		if err := e.client.SetWindStrength(deviceID, strings.ToUpper(step.Fan)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// relax suspends enforcement on a device for the given duration.
+func (e *Engine) relax(deviceID string, duration time.Duration) {
+	e.mu.Lock()
+	e.relaxUntil[deviceID] = time.Now().Add(duration)
+	e.mu.Unlock()
+	e.logf("[%s] Enforcement relaxed for %s", e.alias(deviceID), duration)
+}
+
+// relaxed reports whether enforcement is currently suspended on a device.
+func (e *Engine) relaxed(deviceID string) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return time.Now().Before(e.relaxUntil[deviceID])
+}
@@ -0,0 +1,102 @@
+package rules
+
+import (
+	"controle-arcondicionado/internal/store"
+	"time"
+)
+
+// Snapshot exports the engine's serializable enforcement state.
+func (e *Engine) Snapshot() *store.State {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	state := &store.State{
+		RunningSince:  make(map[string]time.Time, len(e.runningSince)),
+		Modes:         make(map[string]string, len(e.modes)),
+		RelaxUntil:    make(map[string]time.Time, len(e.relaxUntil)),
+		LastDrySwitch: make(map[string]time.Time, len(e.lastDrySwitch)),
+		LastErrors:    make(map[string]string, len(e.lastErrors)),
+		Away:          e.away,
+		AwayUntil:     e.awayUntil,
+		AwayPowerOff:  e.awayPolicy.PowerOff,
+		AwayMinTemp:   e.awayPolicy.MinTemperature,
+		AwayPrecool:   e.awayPrecool,
+	}
+	for deviceID, since := range e.runningSince {
+		state.RunningSince[deviceID] = since
+	}
+	for deviceID, mode := range e.modes {
+		state.Modes[deviceID] = string(mode)
+	}
+	for deviceID, until := range e.relaxUntil {
+		if time.Now().Before(until) {
+			state.RelaxUntil[deviceID] = until
+		}
+	}
+	for deviceID, last := range e.lastDrySwitch {
+		state.LastDrySwitch[deviceID] = last
+	}
+	for deviceID, code := range e.lastErrors {
+		state.LastErrors[deviceID] = code
+	}
+	return state
+}
+
+// Restore loads a previously saved snapshot into the engine. It only
+// fills in state and schedules the away return; it does not re-run the
+// side effects (like powering devices off) the original transitions had.
+func (e *Engine) Restore(state *store.State) {
+	if state == nil {
+		return
+	}
+
+	e.mu.Lock()
+	for deviceID, since := range state.RunningSince {
+		e.runningSince[deviceID] = since
+	}
+	for deviceID, mode := range state.Modes {
+		if parsed, err := ParseMode(mode); err == nil {
+			e.modes[deviceID] = parsed
+		}
+	}
+	for deviceID, until := range state.RelaxUntil {
+		if time.Now().Before(until) {
+			e.relaxUntil[deviceID] = until
+		}
+	}
+	for deviceID, last := range state.LastDrySwitch {
+		e.lastDrySwitch[deviceID] = last
+	}
+	for deviceID, code := range state.LastErrors {
+		e.lastErrors[deviceID] = code
+	}
+	e.away = state.Away
+	if state.Away {
+		// Without the policy the restored away mode would report active
+		// but enforce nothing
+		e.awayPolicy = AwayPolicy{
+			MinTemperature: state.AwayMinTemp,
+			PowerOff:       state.AwayPowerOff,
+		}
+	}
+	if state.Away && !state.AwayUntil.IsZero() {
+		if time.Now().Before(state.AwayUntil) {
+			e.awayUntil = state.AwayUntil
+			e.awayPrecool = state.AwayPrecool
+			if state.AwayPrecool {
+				if lead := time.Until(state.AwayUntil.Add(-time.Hour)); lead > 0 {
+					e.awayTimers = append(e.awayTimers, time.AfterFunc(lead, e.precool))
+				}
+			}
+			e.awayTimers = append(e.awayTimers, time.AfterFunc(time.Until(state.AwayUntil), func() {
+				e.logf("Scheduled return time reached, deactivating away mode")
+				e.SetAway(false, AwayPolicy{})
+			}))
+		} else {
+			e.away = false // the scheduled return passed while we were down
+		}
+	}
+	e.mu.Unlock()
+
+	e.logf("Enforcement state restored (saved %s)", state.SavedAt.Format("2006-01-02 15:04:05"))
+}
@@ -0,0 +1,50 @@
+// Package sched provides timezone-aware scheduling helpers. Daily
+// schedules computed here stick to the configured wall-clock time across
+// DST transitions instead of drifting by the offset change.
+package sched
+
+import "time"
+
+// NextAt returns the next occurrence of the wall-clock time hour:minute in
+// loc, strictly after now. Around DST transitions the wall-clock time is
+// preserved; a time that does not exist on a spring-forward day resolves
+// to the first valid instant after the gap.
+func NextAt(now time.Time, hour, minute int, loc *time.Location) time.Time {
+	local := now.In(loc)
+	next := at(local.Year(), local.Month(), local.Day(), hour, minute, loc)
+	if !next.After(now) {
+		next = at(local.Year(), local.Month(), local.Day()+1, hour, minute, loc)
+	}
+	return next
+}
+
+// at builds the wall-clock time hour:minute on the given day. A time
+// inside a spring-forward gap (normalized away by time.Date) resolves to
+// the first instant after the gap.
+func at(year int, month time.Month, day, hour, minute int, loc *time.Location) time.Time {
+	t := time.Date(year, month, day, hour, minute, 0, 0, loc)
+	if t.Hour() != hour || t.Minute() != minute {
+		return gapEnd(t)
+	}
+	return t
+}
+
+// gapEnd returns the first instant after the DST transition nearest to t,
+// found by binary-searching for the UTC offset change.
+func gapEnd(t time.Time) time.Time {
+	lo, hi := t, t.Add(6*time.Hour)
+	_, offset := lo.Zone()
+	if _, hiOffset := hi.Zone(); hiOffset == offset {
+		// Normalization already landed past the gap; t is valid as-is
+		return t
+	}
+	for hi.Sub(lo) > time.Second {
+		mid := lo.Add(hi.Sub(lo) / 2)
+		if _, midOffset := mid.Zone(); midOffset == offset {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return hi.Truncate(time.Minute)
+}
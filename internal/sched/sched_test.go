@@ -0,0 +1,86 @@
+package sched
+
+import (
+	"testing"
+	"time"
+
+	// Guarantee timezone data even on systems without /usr/share/zoneinfo
+	_ "time/tzdata"
+)
+
+func newYork(t *testing.T) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("failed to load timezone: %v", err)
+	}
+	return loc
+}
+
+func TestNextAtSameDay(t *testing.T) {
+	loc := newYork(t)
+	now := time.Date(2026, time.June, 10, 8, 0, 0, 0, loc)
+
+	next := NextAt(now, 23, 50, loc)
+
+	want := time.Date(2026, time.June, 10, 23, 50, 0, 0, loc)
+	if !next.Equal(want) {
+		t.Errorf("NextAt = %v, want %v", next, want)
+	}
+}
+
+func TestNextAtRollsToNextDay(t *testing.T) {
+	loc := newYork(t)
+	now := time.Date(2026, time.June, 10, 23, 50, 0, 0, loc)
+
+	next := NextAt(now, 23, 50, loc)
+
+	want := time.Date(2026, time.June, 11, 23, 50, 0, 0, loc)
+	if !next.Equal(want) {
+		t.Errorf("NextAt = %v, want %v", next, want)
+	}
+}
+
+func TestNextAtKeepsWallClockAcrossSpringForward(t *testing.T) {
+	loc := newYork(t)
+	// DST starts 2026-03-08 02:00 in New York; the day has only 23 hours.
+	now := time.Date(2026, time.March, 7, 22, 30, 0, 0, loc)
+
+	next := NextAt(now, 22, 0, loc)
+
+	if next.Hour() != 22 || next.Minute() != 0 {
+		t.Errorf("NextAt landed at %02d:%02d, want 22:00", next.Hour(), next.Minute())
+	}
+	if got, want := next.Sub(now), 22*time.Hour+30*time.Minute; got != want {
+		t.Errorf("NextAt is %v away, want %v (short day)", got, want)
+	}
+}
+
+func TestNextAtKeepsWallClockAcrossFallBack(t *testing.T) {
+	loc := newYork(t)
+	// DST ends 2026-11-01 02:00 in New York; the day has 25 hours.
+	now := time.Date(2026, time.October, 31, 23, 0, 0, 0, loc)
+
+	next := NextAt(now, 23, 0, loc)
+
+	if next.Hour() != 23 || next.Minute() != 0 {
+		t.Errorf("NextAt landed at %02d:%02d, want 23:00", next.Hour(), next.Minute())
+	}
+	if got, want := next.Sub(now), 25*time.Hour; got != want {
+		t.Errorf("NextAt is %v away, want %v (long day)", got, want)
+	}
+}
+
+func TestNextAtNonexistentTimeResolvesAfterGap(t *testing.T) {
+	loc := newYork(t)
+	// 02:30 does not exist on 2026-03-08; expect the first valid instant
+	// after the gap rather than an error or a skipped day.
+	now := time.Date(2026, time.March, 8, 1, 0, 0, 0, loc)
+
+	next := NextAt(now, 2, 30, loc)
+
+	want := time.Date(2026, time.March, 8, 3, 0, 0, 0, loc)
+	if !next.Equal(want) {
+		t.Errorf("NextAt = %v, want %v (end of the gap)", next, want)
+	}
+}
@@ -0,0 +1,273 @@
+// Package sim provides a simulated household: a mock ThinQ REST server
+// plus N virtual air conditioners whose rooms drift warm and whose
+// impatient "users" occasionally crank the setpoint down. Events are
+// delivered in-process (standing in for the MQTT feed), so the real
+// enforcement pipeline can be exercised without an LG account.
+package sim
+
+import (
+	"controle-arcondicionado/internal/thinq"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+)
+
+// tick is the simulated time step between event rounds.
+const tick = 2 * time.Second
+
+// virtualAC is one simulated unit and the room it sits in.
+type virtualAC struct {
+	device  thinq.Device
+	powerOn bool
+	mode    string
+	target  float64
+	current float64
+	fan     string
+}
+
+// Household runs the virtual devices and the mock REST server.
+type Household struct {
+	mu     sync.Mutex
+	acs    []*virtualAC
+	server *httptest.Server
+	events chan []byte
+	done   chan struct{}
+	rng    *rand.Rand
+	logf   func(format string, args ...interface{})
+}
+
+// NewHousehold creates n virtual air conditioners, all powered on in COOL
+// mode with warm rooms, and starts the mock REST server and the drift loop.
+func NewHousehold(n int, logf func(format string, args ...interface{})) *Household {
+	h := &Household{
+		events: make(chan []byte, 64),
+		done:   make(chan struct{}),
+		rng:    rand.New(rand.NewSource(time.Now().UnixNano())),
+		logf:   logf,
+	}
+	for i := 1; i <= n; i++ {
+		h.acs = append(h.acs, &virtualAC{
+			device: thinq.Device{
+				DeviceID:   fmt.Sprintf("sim-device-%d", i),
+				DeviceType: "DEVICE_AIR_CONDITIONER",
+				ModelName:  "SIMULATOR",
+				Alias:      fmt.Sprintf("Simulado %d", i),
+				Reportable: true,
+			},
+			powerOn: true,
+			mode:    "COOL",
+			target:  22 + float64(h.rng.Intn(3)),
+			current: 26 + float64(h.rng.Intn(5)),
+			fan:     "AUTO",
+		})
+	}
+
+	h.server = httptest.NewServer(http.HandlerFunc(h.handleREST))
+	go h.loop()
+	return h
+}
+
+// URL returns the mock REST server's base URL.
+func (h *Household) URL() string {
+	return h.server.URL
+}
+
+// Events returns the channel of raw event payloads, in the same shape the
+// real MQTT feed delivers.
+func (h *Household) Events() <-chan []byte {
+	return h.events
+}
+
+// Close stops the simulation and the mock server.
+func (h *Household) Close() {
+	close(h.done)
+	h.server.Close()
+}
+
+// loop advances the simulation one tick at a time: rooms drift, users
+// misbehave, and every device reports its state.
+func (h *Household) loop() {
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			h.step()
+		case <-h.done:
+			return
+		}
+	}
+}
+
+// step runs one round of physics and user behavior.
+func (h *Household) step() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, ac := range h.acs {
+		// Rough room physics: cooling pulls toward the target, an idle
+		// room drifts back toward a warm 30°C
+		switch {
+		case ac.powerOn && cools(ac.mode) && ac.current > ac.target:
+			ac.current -= 0.3
+		case ac.current < 30:
+			ac.current += 0.1
+		}
+
+		// Occasionally an impatient user cranks the setpoint down
+		if ac.powerOn && h.rng.Intn(20) == 0 {
+			ac.target = float64(16 + h.rng.Intn(5))
+			h.logf("[sim] User set %s to %.0f°C", ac.device.Alias, ac.target)
+		}
+
+		h.emitLocked(ac)
+	}
+}
+
+// cools reports whether a job mode actively lowers the room temperature.
+func cools(mode string) bool {
+	switch mode {
+	case "COOL", "AUTO", "AIR_DRY", "JET":
+		return true
+	}
+	return false
+}
+
+// emitLocked queues a DEVICE_STATUS event for the device. Callers must
+// hold h.mu.
+func (h *Household) emitLocked(ac *virtualAC) {
+	payload, _ := json.Marshal(map[string]interface{}{
+		"deviceId": ac.device.DeviceID,
+		"pushType": "DEVICE_STATUS",
+		"report":   reportLocked(ac),
+	})
+	select {
+	case h.events <- payload:
+	default: // drop when the consumer falls behind
+	}
+}
+
+// reportLocked renders the device state in the ThinQ report shape.
+func reportLocked(ac *virtualAC) map[string]interface{} {
+	operationMode := "POWER_OFF"
+	if ac.powerOn {
+		operationMode = "POWER_ON"
+	}
+	return map[string]interface{}{
+		"temperature": map[string]interface{}{
+			"targetTemperature":  ac.target,
+			"currentTemperature": ac.current,
+		},
+		"airConJobMode": map[string]interface{}{"currentJobMode": ac.mode},
+		"operation":     map[string]interface{}{"airConOperationMode": operationMode},
+		"airFlow":       map[string]interface{}{"windStrength": ac.fan},
+	}
+}
+
+// handleREST implements the subset of the ThinQ REST API the pipeline
+// uses: device list, device state and device control.
+func (h *Household) handleREST(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	switch {
+	case r.Method == "GET" && r.URL.Path == "/devices":
+		var response []map[string]interface{}
+		for _, ac := range h.acs {
+			response = append(response, map[string]interface{}{
+				"deviceId": ac.device.DeviceID,
+				"deviceInfo": map[string]interface{}{
+					"deviceType": ac.device.DeviceType,
+					"modelName":  ac.device.ModelName,
+					"alias":      ac.device.Alias,
+					"reportable": ac.device.Reportable,
+				},
+			})
+		}
+		writeJSON(w, map[string]interface{}{"response": response})
+
+	case r.Method == "GET" && strings.HasSuffix(r.URL.Path, "/state"):
+		ac := h.deviceLocked(pathDevice(r.URL.Path))
+		if ac == nil {
+			http.NotFound(w, r)
+			return
+		}
+		writeJSON(w, map[string]interface{}{"response": reportLocked(ac)})
+
+	case r.Method == "POST" && strings.HasSuffix(r.URL.Path, "/control"):
+		ac := h.deviceLocked(pathDevice(r.URL.Path))
+		if ac == nil {
+			http.NotFound(w, r)
+			return
+		}
+		var payload map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		h.applyControlLocked(ac, payload)
+		writeJSON(w, map[string]interface{}{"response": map[string]interface{}{}})
+
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// applyControlLocked applies a REST control payload to a virtual device
+// and emits the resulting state event. Callers must hold h.mu.
+func (h *Household) applyControlLocked(ac *virtualAC, payload map[string]interface{}) {
+	if temperature, ok := payload["temperature"].(map[string]interface{}); ok {
+		if target, ok := temperature["targetTemperature"].(float64); ok {
+			ac.target = target
+			h.logf("[sim] Control: %s target set to %.0f°C", ac.device.Alias, target)
+		}
+	}
+	if operation, ok := payload["operation"].(map[string]interface{}); ok {
+		if mode, ok := operation["airConOperationMode"].(string); ok {
+			ac.powerOn = mode == "POWER_ON"
+			h.logf("[sim] Control: %s power set to %v", ac.device.Alias, ac.powerOn)
+		}
+	}
+	if jobMode, ok := payload["airConJobMode"].(map[string]interface{}); ok {
+		if mode, ok := jobMode["currentJobMode"].(string); ok {
+			ac.mode = mode
+			h.logf("[sim] Control: %s mode set to %s", ac.device.Alias, mode)
+		}
+	}
+	if airFlow, ok := payload["airFlow"].(map[string]interface{}); ok {
+		if strength, ok := airFlow["windStrength"].(string); ok {
+			ac.fan = strength
+		}
+	}
+	h.emitLocked(ac)
+}
+
+// deviceLocked finds a virtual device by ID. Callers must hold h.mu.
+func (h *Household) deviceLocked(deviceID string) *virtualAC {
+	for _, ac := range h.acs {
+		if ac.device.DeviceID == deviceID {
+			return ac
+		}
+	}
+	return nil
+}
+
+// pathDevice extracts the device ID from /devices/{id}/....
+func pathDevice(path string) string {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) < 2 {
+		return ""
+	}
+	return parts[1]
+}
+
+// writeJSON writes a JSON response body.
+func writeJSON(w http.ResponseWriter, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(value)
+}
@@ -0,0 +1,230 @@
+// Package statecache keeps the last known state of every device in one
+// place. Events and polls feed it; the REST API, the metrics endpoint and
+// the rules engine read from it instead of each re-deriving state from raw
+// events. Every field carries the timestamp of its last update, and a
+// device whose state has not been refreshed recently is flagged as stale.
+package statecache
+
+import (
+	"controle-arcondicionado/internal/thinq"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultStaleAfter flags a device as stale when nothing — neither an
+// event nor a poll — has updated it for this long. Polling runs every
+// five minutes, so a healthy device never gets near it.
+const DefaultStaleAfter = 15 * time.Minute
+
+// DeviceState is the last known state of one device. Pointer fields are
+// nil until the corresponding value has been seen. FieldUpdated records
+// when each field last changed, keyed by its JSON name.
+type DeviceState struct {
+	DeviceID           string               `json:"deviceId"`
+	Alias              string               `json:"alias"`
+	CurrentTemperature *float64             `json:"currentTemperature,omitempty"`
+	TargetTemperature  *float64             `json:"targetTemperature,omitempty"`
+	CoolTarget         *float64             `json:"coolTarget,omitempty"`
+	HeatTarget         *float64             `json:"heatTarget,omitempty"`
+	Humidity           *float64             `json:"humidity,omitempty"`
+	JobMode            string               `json:"jobMode,omitempty"`
+	PowerOn            *bool                `json:"powerOn,omitempty"`
+	FieldUpdated       map[string]time.Time `json:"fieldUpdated,omitempty"`
+	UpdatedAt          time.Time            `json:"updatedAt,omitempty"`
+	Stale              bool                 `json:"stale"` // computed when read
+}
+
+// Cache is the shared last-known-state cache, keyed by device ID.
+type Cache struct {
+	mu         sync.Mutex
+	staleAfter time.Duration
+	states     map[string]*DeviceState
+	stats      map[string]*deviceStats // activity counters, see stats.go
+}
+
+// New creates a cache with an empty entry per device. staleAfter controls
+// when a silent device is flagged as stale (0 uses DefaultStaleAfter).
+func New(devices []thinq.Device, staleAfter time.Duration) *Cache {
+	if staleAfter == 0 {
+		staleAfter = DefaultStaleAfter
+	}
+	states := make(map[string]*DeviceState, len(devices))
+	stats := make(map[string]*deviceStats, len(devices))
+	for _, device := range devices {
+		states[device.DeviceID] = &DeviceState{
+			DeviceID:     device.DeviceID,
+			Alias:        device.Alias,
+			FieldUpdated: make(map[string]time.Time),
+		}
+		stats[device.DeviceID] = &deviceStats{}
+	}
+	return &Cache{staleAfter: staleAfter, states: states, stats: stats}
+}
+
+// Observe folds a device event into the cache, stamping every field the
+// event carries. Events for unknown devices are ignored.
+func (c *Cache) Observe(event *thinq.Event) {
+	if event.PushType != "DEVICE_STATUS" {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	state, ok := c.states[event.DeviceID]
+	if !ok {
+		return
+	}
+	now := time.Now()
+	if event.CurrentTemperature != nil {
+		state.CurrentTemperature = event.CurrentTemperature
+		state.FieldUpdated["currentTemperature"] = now
+	}
+	if event.TargetTemperature != nil {
+		state.TargetTemperature = event.TargetTemperature
+		state.FieldUpdated["targetTemperature"] = now
+	}
+	if event.CoolTarget != nil {
+		state.CoolTarget = event.CoolTarget
+		state.FieldUpdated["coolTarget"] = now
+	}
+	if event.HeatTarget != nil {
+		state.HeatTarget = event.HeatTarget
+		state.FieldUpdated["heatTarget"] = now
+	}
+	if event.Humidity != nil {
+		state.Humidity = event.Humidity
+		state.FieldUpdated["humidity"] = now
+	}
+	if event.JobMode != "" {
+		state.JobMode = event.JobMode
+		state.FieldUpdated["jobMode"] = now
+	}
+	if event.PowerOn != nil {
+		state.PowerOn = event.PowerOn
+		state.FieldUpdated["powerOn"] = now
+	}
+	state.UpdatedAt = now
+
+	if stats, ok := c.stats[event.DeviceID]; ok {
+		c.observeStatsLocked(stats, event, now)
+	}
+}
+
+// Get returns a copy of a device's state with its staleness computed.
+func (c *Cache) Get(deviceID string) (DeviceState, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	state, ok := c.states[deviceID]
+	if !ok {
+		return DeviceState{}, false
+	}
+	return c.copyLocked(state), true
+}
+
+// All returns a copy of every device's state, sorted by alias.
+func (c *Cache) All() []DeviceState {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	all := make([]DeviceState, 0, len(c.states))
+	for _, state := range c.states {
+		all = append(all, c.copyLocked(state))
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Alias < all[j].Alias })
+	return all
+}
+
+// copyLocked deep-copies a state and computes its staleness. Callers must
+// hold c.mu.
+func (c *Cache) copyLocked(state *DeviceState) DeviceState {
+	view := *state
+	view.CurrentTemperature = copyFloat(state.CurrentTemperature)
+	view.TargetTemperature = copyFloat(state.TargetTemperature)
+	view.CoolTarget = copyFloat(state.CoolTarget)
+	view.HeatTarget = copyFloat(state.HeatTarget)
+	view.Humidity = copyFloat(state.Humidity)
+	view.PowerOn = copyBool(state.PowerOn)
+	view.FieldUpdated = make(map[string]time.Time, len(state.FieldUpdated))
+	for field, at := range state.FieldUpdated {
+		view.FieldUpdated[field] = at
+	}
+	view.Stale = state.UpdatedAt.IsZero() || time.Since(state.UpdatedAt) > c.staleAfter
+	return view
+}
+
+// Load restores a persisted cache, keeping only devices that still exist.
+// A missing file is not an error.
+func (c *Cache) Load(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read state cache: %w", err)
+	}
+
+	var saved map[string]*DeviceState
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return fmt.Errorf("failed to parse state cache %s: %w", path, err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for deviceID, state := range saved {
+		current, ok := c.states[deviceID]
+		if !ok {
+			continue
+		}
+		alias := current.Alias // the device list is authoritative
+		*current = *state
+		current.DeviceID = deviceID
+		current.Alias = alias
+		if current.FieldUpdated == nil {
+			current.FieldUpdated = make(map[string]time.Time)
+		}
+	}
+	return nil
+}
+
+// Save writes the cache atomically (write to a temp file, then rename).
+func (c *Cache) Save(path string) error {
+	c.mu.Lock()
+	data, err := json.MarshalIndent(c.states, "", "  ")
+	c.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to marshal state cache: %w", err)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write state cache: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to replace state cache: %w", err)
+	}
+	return nil
+}
+
+// copyFloat clones an optional float value.
+func copyFloat(value *float64) *float64 {
+	if value == nil {
+		return nil
+	}
+	clone := *value
+	return &clone
+}
+
+// copyBool clones an optional bool value.
+func copyBool(value *bool) *bool {
+	if value == nil {
+		return nil
+	}
+	clone := *value
+	return &clone
+}
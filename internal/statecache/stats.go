@@ -0,0 +1,109 @@
+package statecache
+
+import (
+	"controle-arcondicionado/internal/thinq"
+	"sort"
+	"time"
+)
+
+// DeviceStats are per-device activity counters kept alongside the state
+// cache. Events, corrections and the setpoint average accumulate since the
+// daemon started; the powered-on time resets at local midnight.
+type DeviceStats struct {
+	DeviceID       string  `json:"deviceId"`
+	Alias          string  `json:"alias"`
+	Events         int64   `json:"events"`
+	Corrections    int64   `json:"corrections"`
+	MinutesOnToday int     `json:"minutesOnToday"`
+	AvgSetpoint    float64 `json:"avgSetpoint,omitempty"` // 0 when no setpoint was seen
+}
+
+// deviceStats is the internal mutable form of one device's counters.
+type deviceStats struct {
+	events        int64
+	corrections   int64
+	setpointSum   float64
+	setpointCount int64
+	onToday       time.Duration // completed runtime today
+	onSince       time.Time     // start of the current run (zero while off)
+	day           time.Time     // midnight the onToday total belongs to
+}
+
+// roll resets the daily runtime when the day changed since the last
+// update. A run crossing midnight is credited to the new day from 00:00.
+func (s *deviceStats) roll(now time.Time) {
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	if s.day.Equal(midnight) {
+		return
+	}
+	if !s.onSince.IsZero() && s.onSince.Before(midnight) {
+		s.onSince = midnight
+	}
+	s.onToday = 0
+	s.day = midnight
+}
+
+// observeStatsLocked folds an event into the device's counters. Callers
+// must hold c.mu.
+func (c *Cache) observeStatsLocked(stats *deviceStats, event *thinq.Event, now time.Time) {
+	stats.roll(now)
+	stats.events++
+
+	if event.TargetTemperature != nil {
+		stats.setpointSum += *event.TargetTemperature
+		stats.setpointCount++
+	}
+
+	if event.PowerOn != nil {
+		switch {
+		case *event.PowerOn && stats.onSince.IsZero():
+			stats.onSince = now
+		case !*event.PowerOn && !stats.onSince.IsZero():
+			stats.onToday += now.Sub(stats.onSince)
+			stats.onSince = time.Time{}
+		}
+	}
+}
+
+// NoteCorrection counts a policy correction made against a device.
+func (c *Cache) NoteCorrection(deviceID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if stats, ok := c.stats[deviceID]; ok {
+		stats.roll(time.Now())
+		stats.corrections++
+	}
+}
+
+// Stats returns every device's counters, sorted by alias. The powered-on
+// time includes the current run for devices that are still on.
+func (c *Cache) Stats() []DeviceStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	all := make([]DeviceStats, 0, len(c.stats))
+	for deviceID, stats := range c.stats {
+		stats.roll(now)
+		onToday := stats.onToday
+		if !stats.onSince.IsZero() {
+			onToday += now.Sub(stats.onSince)
+		}
+
+		view := DeviceStats{
+			DeviceID:       deviceID,
+			Events:         stats.events,
+			Corrections:    stats.corrections,
+			MinutesOnToday: int(onToday.Minutes()),
+		}
+		if state, ok := c.states[deviceID]; ok {
+			view.Alias = state.Alias
+		}
+		if stats.setpointCount > 0 {
+			view.AvgSetpoint = stats.setpointSum / float64(stats.setpointCount)
+		}
+		all = append(all, view)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Alias < all[j].Alias })
+	return all
+}
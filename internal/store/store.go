@@ -0,0 +1,71 @@
+// Package store persists the engine's per-device enforcement state so a
+// daemon restart does not lose running timers, cooldowns, runtime mode
+// overrides or away status.
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// State is the serializable snapshot of the engine's enforcement state.
+type State struct {
+	SavedAt       time.Time            `json:"savedAt"`
+	RunningSince  map[string]time.Time `json:"runningSince,omitempty"`
+	Modes         map[string]string    `json:"modes,omitempty"`
+	RelaxUntil    map[string]time.Time `json:"relaxUntil,omitempty"`
+	LastDrySwitch map[string]time.Time `json:"lastDrySwitch,omitempty"`
+	LastErrors    map[string]string    `json:"lastErrors,omitempty"`
+	Away          bool                 `json:"away,omitempty"`
+	AwayUntil     time.Time            `json:"awayUntil,omitempty"`
+	AwayPowerOff  bool                 `json:"awayPowerOff,omitempty"`
+	AwayMinTemp   int                  `json:"awayMinTemp,omitempty"`
+	AwayPrecool   bool                 `json:"awayPrecool,omitempty"`
+}
+
+// Store reads and writes state snapshots at a fixed path.
+type Store struct {
+	path string
+}
+
+// Open creates a store persisting at the given path.
+func Open(path string) *Store {
+	return &Store{path: path}
+}
+
+// Load reads the last snapshot. A missing file returns nil without error.
+func (s *Store) Load() (*State, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read state file: %w", err)
+	}
+
+	var state State
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse state file %s: %w", s.path, err)
+	}
+	return &state, nil
+}
+
+// Save writes a snapshot atomically (write to a temp file, then rename).
+func (s *Store) Save(state *State) error {
+	state.SavedAt = time.Now()
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal state: %w", err)
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("failed to replace state file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+// Package systemd implements the sd_notify protocol so the daemon can run
+// as a Type=notify service with watchdog supervision, without cgo or
+// external dependencies. All functions are no-ops outside systemd.
+package systemd
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Notify sends a state message to the systemd notification socket.
+// It returns nil without doing anything when NOTIFY_SOCKET is not set.
+func Notify(state string) error {
+	socket := os.Getenv("NOTIFY_SOCKET")
+	if socket == "" {
+		return nil
+	}
+
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{
+		Name: socket,
+		Net:  "unixgram",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to connect to notify socket: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write([]byte(state)); err != nil {
+		return fmt.Errorf("failed to write to notify socket: %w", err)
+	}
+
+	return nil
+}
+
+// NotifyReady tells systemd the service finished starting up.
+func NotifyReady() error {
+	return Notify("READY=1")
+}
+
+// NotifyStopping tells systemd the service began shutting down.
+func NotifyStopping() error {
+	return Notify("STOPPING=1")
+}
+
+// NotifyWatchdog sends a watchdog keepalive.
+func NotifyWatchdog() error {
+	return Notify("WATCHDOG=1")
+}
+
+// WatchdogInterval returns the interval at which keepalives should be sent
+// (half the configured WatchdogSec, per systemd recommendation) and whether
+// the watchdog is enabled for this process.
+func WatchdogInterval() (time.Duration, bool) {
+	usecStr := os.Getenv("WATCHDOG_USEC")
+	if usecStr == "" {
+		return 0, false
+	}
+
+	// WATCHDOG_PID, when set, must match this process
+	if pidStr := os.Getenv("WATCHDOG_PID"); pidStr != "" {
+		pid, err := strconv.Atoi(pidStr)
+		if err != nil || pid != os.Getpid() {
+			return 0, false
+		}
+	}
+
+	usec, err := strconv.ParseInt(usecStr, 10, 64)
+	if err != nil || usec <= 0 {
+		return 0, false
+	}
+
+	return time.Duration(usec) * time.Microsecond / 2, true
+}
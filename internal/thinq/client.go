@@ -16,19 +16,25 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 )
 
 const (
-	baseURL    = "https://api-aic.lgthinq.com"
+	defaultBaseURL = "https://api-aic.lgthinq.com"
 	apiKey     = "v6GFvkweNo7DK7yD3ylIZ9w52aKBU0eJ7wLXkSR3"
 )
 
 // Client represents a ThinQ API client
 type Client struct {
 	httpClient  *http.Client
+	baseURL     string
 	accessToken string
 	countryCode string
 	clientID    string
+	mqttControl *mqttControl
+	deviceTypes map[string]string // deviceID → device type, cached by GetDeviceList
+	logf        func(format string, args ...interface{}) // optional, see SetLogf
+	schemaMismatches int64 // responses that needed a fallback schema (atomic)
 }
 
 // NewClient creates a new ThinQ API client
@@ -36,16 +42,25 @@ func NewClient(accessToken, countryCode, clientID string) *Client {
 	return &Client{
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
+			// No-op unless a tracer provider is installed (see internal/tracing)
+			Transport: otelhttp.NewTransport(http.DefaultTransport),
 		},
+		baseURL:     defaultBaseURL,
 		accessToken: accessToken,
 		countryCode: countryCode,
 		clientID:    clientID,
 	}
 }
 
+// SetBaseURL points the client at a different API endpoint. Used by the
+// simulator to run against a local mock server.
+func (c *Client) SetBaseURL(url string) {
+	c.baseURL = strings.TrimSuffix(url, "/")
+}
+
 // GetDeviceList retrieves the list of devices associated with the account
 func (c *Client) GetDeviceList() ([]Device, error) {
-	url := fmt.Sprintf("%s/devices", baseURL)
+	url := fmt.Sprintf("%s/devices", c.baseURL)
 
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
@@ -80,14 +95,14 @@ func (c *Client) GetDeviceList() ([]Device, error) {
 		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
 	}
 
-	var deviceResp DeviceListResponse
-	if err := json.Unmarshal(body, &deviceResp); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+	deviceList, err := c.decodeDeviceList(body)
+	if err != nil {
+		return nil, err
 	}
 
 	// Convert DeviceResponse to Device
-	devices := make([]Device, len(deviceResp.Response))
-	for i, dr := range deviceResp.Response {
+	devices := make([]Device, len(deviceList))
+	for i, dr := range deviceList {
 		devices[i] = Device{
 			DeviceID:   dr.DeviceID,
 			DeviceType: dr.DeviceInfo.DeviceType,
@@ -97,12 +112,20 @@ func (c *Client) GetDeviceList() ([]Device, error) {
 		}
 	}
 
+	// Remember each device's type so controls use the right resource names
+	if c.deviceTypes == nil {
+		c.deviceTypes = make(map[string]string)
+	}
+	for _, device := range devices {
+		c.deviceTypes[device.DeviceID] = device.DeviceType
+	}
+
 	return devices, nil
 }
 
 // GetMQTTRoute retrieves MQTT broker information
 func (c *Client) GetMQTTRoute() (string, error) {
-	url := fmt.Sprintf("%s/route", baseURL)
+	url := fmt.Sprintf("%s/route", c.baseURL)
 
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
@@ -150,10 +173,13 @@ func (c *Client) setHeaders(req *http.Request) {
 	req.Header.Set("Content-Type", "application/json")
 }
 
-// GetMQTTCredentials retrieves MQTT credentials (certificate, private key, etc.)
+// GetMQTTCredentials retrieves MQTT credentials (certificate, private key,
+// etc.). It is a compatibility wrapper around the composable steps
+// RegisterClient + IssueCertificate; prefer CredentialManager for reuse
+// across restarts.
 func (c *Client) GetMQTTCredentials() (*MQTTCredentials, error) {
 	// Step 1: Register client
-	if err := c.registerClient(); err != nil {
+	if err := c.RegisterClient(); err != nil {
 		return nil, fmt.Errorf("failed to register client: %w", err)
 	}
 
@@ -164,6 +190,22 @@ func (c *Client) GetMQTTCredentials() (*MQTTCredentials, error) {
 	}
 
 	// Step 3: Request certificate from API
+	certInfo, err := c.IssueCertificate(csrPEM)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MQTTCredentials{
+		Certificate:   certInfo.CertificatePem,
+		PrivateKey:    privateKey,
+		Subscriptions: certInfo.Subscriptions,
+		Publications:  certInfo.Publications,
+	}, nil
+}
+
+// IssueCertificate submits a CSR and returns the signed certificate along
+// with the MQTT topics granted to it.
+func (c *Client) IssueCertificate(csrPEM string) (*CertificateInfo, error) {
 	certReq := CertificateRequest{
 		ServiceCode: "SVC202",
 		CSR:         csrPEM,
@@ -173,7 +215,7 @@ func (c *Client) GetMQTTCredentials() (*MQTTCredentials, error) {
 		return nil, fmt.Errorf("failed to marshal certificate request: %w", err)
 	}
 
-	url := fmt.Sprintf("%s/client/certificate", baseURL)
+	url := fmt.Sprintf("%s/client/certificate", c.baseURL)
 	req, err := http.NewRequest("POST", url, bytes.NewBuffer(reqBody))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
@@ -201,11 +243,7 @@ func (c *Client) GetMQTTCredentials() (*MQTTCredentials, error) {
 		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
 
-	return &MQTTCredentials{
-		Certificate:   certResp.Response.Result.CertificatePem,
-		PrivateKey:    privateKey,
-		Subscriptions: certResp.Response.Result.Subscriptions,
-	}, nil
+	return &certResp.Response.Result, nil
 }
 
 // MQTTCredentials contains all credentials needed for MQTT connection
@@ -213,10 +251,11 @@ type MQTTCredentials struct {
 	Certificate   string
 	PrivateKey    string
 	Subscriptions []string
+	Publications  []string
 }
 
-// registerClient registers the client with the ThinQ API
-func (c *Client) registerClient() error {
+// RegisterClient registers the client with the ThinQ API
+func (c *Client) RegisterClient() error {
 	regReq := ClientRegisterRequest{
 		Type:        "MQTT",
 		ServiceCode: "SVC202",
@@ -229,7 +268,7 @@ func (c *Client) registerClient() error {
 		return fmt.Errorf("failed to marshal register request: %w", err)
 	}
 
-	url := fmt.Sprintf("%s/client", baseURL)
+	url := fmt.Sprintf("%s/client", c.baseURL)
 	req, err := http.NewRequest("POST", url, bytes.NewBuffer(reqBody))
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
@@ -295,7 +334,7 @@ func generateCSR(clientID string) (string, string, error) {
 
 // SubscribeToDeviceEvents subscribes to events for a specific device
 func (c *Client) SubscribeToDeviceEvents(deviceID string) error {
-	url := fmt.Sprintf("%s/event/%s/subscribe", baseURL, deviceID)
+	url := fmt.Sprintf("%s/event/%s/subscribe", c.baseURL, deviceID)
 
 	// Event subscription requires expiration time
 	payload := map[string]interface{}{
@@ -338,7 +377,7 @@ func (c *Client) SubscribeToDeviceEvents(deviceID string) error {
 
 // SubscribeToPushNotifications subscribes to push notifications for a specific device
 func (c *Client) SubscribeToPushNotifications(deviceID string) error {
-	url := fmt.Sprintf("%s/push/%s/subscribe", baseURL, deviceID)
+	url := fmt.Sprintf("%s/push/%s/subscribe", c.baseURL, deviceID)
 
 	req, err := http.NewRequest("POST", url, nil)
 	if err != nil {
@@ -377,16 +416,43 @@ func (c *Client) SubscribeToPushNotifications(deviceID string) error {
 	return nil
 }
 
-// SetTemperature sets the target temperature for a device
-func (c *Client) SetTemperature(deviceID string, temperature int) error {
-	url := fmt.Sprintf("%s/devices/%s/control", baseURL, deviceID)
+// GetDeviceState polls the current state of a device
+func (c *Client) GetDeviceState(deviceID string) (map[string]interface{}, error) {
+	url := fmt.Sprintf("%s/devices/%s/state", c.baseURL, deviceID)
 
-	// Payload format without dataSetList wrapper - send resource directly
-	payload := map[string]interface{}{
-		"temperature": map[string]interface{}{
-			"targetTemperature": temperature,
-		},
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	c.setHeaders(req)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	return c.decodeObjectEnvelope(fmt.Sprintf("/devices/%s/state", deviceID), body)
+}
+
+// control sends a control payload to a device, over MQTT when that path
+// has been enabled (see EnableMQTTControl) and over REST otherwise
+func (c *Client) control(deviceID string, payload map[string]interface{}) error {
+	if c.mqttControl != nil {
+		return c.mqttControl.send(deviceID, payload)
+	}
+
+	url := fmt.Sprintf("%s/devices/%s/control", c.baseURL, deviceID)
 
 	reqBody, err := json.Marshal(payload)
 	if err != nil {
@@ -419,6 +485,105 @@ func (c *Client) SetTemperature(deviceID string, temperature int) error {
 	return nil
 }
 
+// SetTemperature sets the target temperature for a device
+func (c *Client) SetTemperature(deviceID string, temperature int) error {
+	// Payload format without dataSetList wrapper - send resource directly
+	return c.control(deviceID, map[string]interface{}{
+		"temperature": map[string]interface{}{
+			"targetTemperature": temperature,
+		},
+	})
+}
+
+// SetPower turns a device on or off
+func (c *Client) SetPower(deviceID string, on bool) error {
+	mode := "POWER_OFF"
+	if on {
+		mode = "POWER_ON"
+	}
+	return c.control(deviceID, map[string]interface{}{
+		"operation": map[string]interface{}{
+			c.operationKey(deviceID): mode,
+		},
+	})
+}
+
+// operationKey returns the operation resource name for the device's type;
+// air conditioners are assumed when the type is unknown
+func (c *Client) operationKey(deviceID string) string {
+	switch c.deviceTypes[deviceID] {
+	case DeviceTypeDehumidifier:
+		return "dehumidifierOperationMode"
+	case DeviceTypeAirPurifier:
+		return "airPurifierOperationMode"
+	}
+	return "airConOperationMode"
+}
+
+// SetJobMode changes the device's job mode (COOL, JET, FAN, AIR_DRY, ...)
+func (c *Client) SetJobMode(deviceID string, mode string) error {
+	return c.control(deviceID, map[string]interface{}{
+		"airConJobMode": map[string]interface{}{
+			"currentJobMode": mode,
+		},
+	})
+}
+
+// SetTwoSetTemperature sets the cool/heat comfort range used in AUTO mode.
+// Bounds set to zero are left unchanged on the device.
+func (c *Client) SetTwoSetTemperature(deviceID string, coolTarget, heatTarget int) error {
+	twoSet := map[string]interface{}{}
+	if coolTarget > 0 {
+		twoSet["coolTargetTemperature"] = coolTarget
+	}
+	if heatTarget > 0 {
+		twoSet["heatTargetTemperature"] = heatTarget
+	}
+	return c.control(deviceID, map[string]interface{}{
+		"twoSetTemperature": twoSet,
+	})
+}
+
+// SetWindStrength sets the fan speed (LOW, MID, HIGH, AUTO, ...)
+func (c *Client) SetWindStrength(deviceID string, strength string) error {
+	return c.control(deviceID, map[string]interface{}{
+		"airFlow": map[string]interface{}{
+			"windStrength": strength,
+		},
+	})
+}
+
+// SetDisplayLight turns the unit's LED display on or off
+func (c *Client) SetDisplayLight(deviceID string, on bool) error {
+	state := "OFF"
+	if on {
+		state = "ON"
+	}
+	return c.control(deviceID, map[string]interface{}{
+		"display": map[string]interface{}{
+			"light": state,
+		},
+	})
+}
+
+// SetTargetHumidity sets the target relative humidity of a dehumidifier
+func (c *Client) SetTargetHumidity(deviceID string, humidity int) error {
+	return c.control(deviceID, map[string]interface{}{
+		"humidity": map[string]interface{}{
+			"targetHumidity": humidity,
+		},
+	})
+}
+
+// SetPowerSave enables or disables the device's power-saving mode
+func (c *Client) SetPowerSave(deviceID string, on bool) error {
+	return c.control(deviceID, map[string]interface{}{
+		"powerSave": map[string]interface{}{
+			"powerSaveEnabled": on,
+		},
+	})
+}
+
 // generateMessageID creates a unique message ID for each request
 func generateMessageID() string {
 	id := uuid.New()
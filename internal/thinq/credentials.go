@@ -0,0 +1,96 @@
+package thinq
+
+import (
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"time"
+)
+
+// rotateBefore is how long before certificate expiry the manager reissues.
+const rotateBefore = 24 * time.Hour
+
+// CredentialManager caches MQTT credentials on disk so restarts reuse the
+// existing certificate instead of issuing a new one, and rotates the
+// certificate when it approaches expiry.
+type CredentialManager struct {
+	client *Client
+	path   string
+}
+
+// NewCredentialManager creates a manager caching credentials at path.
+func NewCredentialManager(client *Client, path string) *CredentialManager {
+	return &CredentialManager{
+		client: client,
+		path:   path,
+	}
+}
+
+// Credentials returns cached credentials when they are still valid,
+// otherwise issues fresh ones and updates the cache.
+func (m *CredentialManager) Credentials() (*MQTTCredentials, error) {
+	if credentials, err := m.load(); err == nil {
+		if expiry, err := certificateExpiry(credentials.Certificate); err == nil &&
+			time.Until(expiry) > rotateBefore {
+			return credentials, nil
+		}
+	}
+	return m.Rotate()
+}
+
+// Rotate issues fresh credentials and replaces the cache.
+func (m *CredentialManager) Rotate() (*MQTTCredentials, error) {
+	credentials, err := m.client.GetMQTTCredentials()
+	if err != nil {
+		return nil, err
+	}
+	if err := m.save(credentials); err != nil {
+		return nil, fmt.Errorf("failed to cache credentials: %w", err)
+	}
+	return credentials, nil
+}
+
+// load reads the cached credentials from disk.
+func (m *CredentialManager) load() (*MQTTCredentials, error) {
+	data, err := os.ReadFile(m.path)
+	if err != nil {
+		return nil, err
+	}
+
+	var credentials MQTTCredentials
+	if err := json.Unmarshal(data, &credentials); err != nil {
+		return nil, fmt.Errorf("failed to parse credential cache: %w", err)
+	}
+	if credentials.Certificate == "" || credentials.PrivateKey == "" {
+		return nil, fmt.Errorf("credential cache is incomplete")
+	}
+
+	return &credentials, nil
+}
+
+// save writes the credentials to disk, readable only by the owner since
+// they include the private key.
+func (m *CredentialManager) save(credentials *MQTTCredentials) error {
+	data, err := json.MarshalIndent(credentials, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal credentials: %w", err)
+	}
+	return os.WriteFile(m.path, data, 0600)
+}
+
+// certificateExpiry parses the PEM certificate and returns its NotAfter.
+func certificateExpiry(certPEM string) (time.Time, error) {
+	block, _ := pem.Decode([]byte(certPEM))
+	if block == nil {
+		return time.Time{}, fmt.Errorf("failed to decode certificate PEM")
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse certificate: %w", err)
+	}
+
+	return cert.NotAfter, nil
+}
@@ -0,0 +1,151 @@
+package thinq
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+// maxLoggedPayload caps how much of a mismatching payload goes to the log.
+const maxLoggedPayload = 512
+
+// SetLogf makes the client log schema mismatches and other non-fatal
+// decoding issues. Without it the client stays silent, as before.
+func (c *Client) SetLogf(logf func(format string, args ...interface{})) {
+	c.logf = logf
+}
+
+// SchemaMismatches returns how many API responses did not match the
+// primary schema of their endpoint since the client was created. Exported
+// as the thinq_schema_mismatch_total metric so a quiet API change is
+// noticed before enforcement silently stops.
+func (c *Client) SchemaMismatches() int64 {
+	return atomic.LoadInt64(&c.schemaMismatches)
+}
+
+// noteSchemaMismatch counts a response that needed a fallback schema (or
+// matched none) and logs it with a redacted copy of the payload.
+func (c *Client) noteSchemaMismatch(endpoint, detail string, body []byte) {
+	atomic.AddInt64(&c.schemaMismatches, 1)
+	if c.logf != nil {
+		c.logf("ThinQ schema mismatch on %s: %s (payload: %s)", endpoint, detail, redactPayload(body))
+	}
+}
+
+// decodeObjectEnvelope extracts the payload object of an endpoint that
+// normally answers {"response": {...}}. Known variants ({"result": {...}}
+// and the bare object) are tried next; using one counts as a schema
+// mismatch but still succeeds, so enforcement keeps working while the log
+// and the metric flag the change.
+func (c *Client) decodeObjectEnvelope(endpoint string, body []byte) (map[string]interface{}, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(body, &raw); err != nil {
+		c.noteSchemaMismatch(endpoint, "not a JSON object", body)
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	var payload map[string]interface{}
+	if inner, ok := raw["response"]; ok {
+		if err := json.Unmarshal(inner, &payload); err == nil {
+			return payload, nil
+		}
+	}
+	if inner, ok := raw["result"]; ok {
+		if err := json.Unmarshal(inner, &payload); err == nil {
+			c.noteSchemaMismatch(endpoint, `payload under "result" instead of "response"`, body)
+			return payload, nil
+		}
+	}
+	// Some variants drop the envelope and answer with the object directly
+	_, hasResponse := raw["response"]
+	_, hasResult := raw["result"]
+	if !hasResponse && !hasResult {
+		if err := json.Unmarshal(body, &payload); err == nil {
+			c.noteSchemaMismatch(endpoint, "payload without envelope", body)
+			return payload, nil
+		}
+	}
+
+	c.noteSchemaMismatch(endpoint, "no known schema variant matched", body)
+	return nil, fmt.Errorf("response matches no known schema for %s", endpoint)
+}
+
+// decodeDeviceList extracts the device array of the /devices endpoint,
+// trying the same envelope variants as decodeObjectEnvelope.
+func (c *Client) decodeDeviceList(body []byte) ([]DeviceResponse, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(body, &raw); err == nil {
+		var list []DeviceResponse
+		if inner, ok := raw["response"]; ok {
+			if err := json.Unmarshal(inner, &list); err == nil {
+				return list, nil
+			}
+		}
+		if inner, ok := raw["result"]; ok {
+			if err := json.Unmarshal(inner, &list); err == nil {
+				c.noteSchemaMismatch("/devices", `payload under "result" instead of "response"`, body)
+				return list, nil
+			}
+		}
+	} else {
+		// Some variants answer with the bare array
+		var list []DeviceResponse
+		if err := json.Unmarshal(body, &list); err == nil {
+			c.noteSchemaMismatch("/devices", "payload without envelope", body)
+			return list, nil
+		}
+	}
+
+	c.noteSchemaMismatch("/devices", "no known schema variant matched", body)
+	return nil, fmt.Errorf("response matches no known schema for /devices")
+}
+
+// redactedKeys are payload fields whose values never belong in a log.
+var redactedKeys = []string{"token", "certificate", "csr", "key", "deviceid", "clientid"}
+
+// redactPayload renders a payload for logging with sensitive values
+// masked and the result truncated. The structure is kept so a schema
+// change is still recognizable from the log line alone.
+func redactPayload(body []byte) string {
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		// Not JSON: log only its size, the content could be anything
+		return fmt.Sprintf("<%d bytes of non-JSON>", len(body))
+	}
+
+	redacted, err := json.Marshal(redactValue("", value))
+	if err != nil {
+		return fmt.Sprintf("<%d bytes>", len(body))
+	}
+	if len(redacted) > maxLoggedPayload {
+		return string(redacted[:maxLoggedPayload]) + "..."
+	}
+	return string(redacted)
+}
+
+// redactValue walks a decoded JSON value, masking values under sensitive keys.
+func redactValue(key string, value interface{}) interface{} {
+	lower := strings.ToLower(key)
+	for _, sensitive := range redactedKeys {
+		if strings.Contains(lower, sensitive) {
+			return "<redacted>"
+		}
+	}
+
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(typed))
+		for k, v := range typed {
+			out[k] = redactValue(k, v)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(typed))
+		for i, v := range typed {
+			out[i] = redactValue(key, v)
+		}
+		return out
+	}
+	return value
+}
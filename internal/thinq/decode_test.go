@@ -0,0 +1,158 @@
+package thinq
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDecodeObjectEnvelopeVariants(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantKey    string
+		wantErr    bool
+		mismatches int64
+	}{
+		{
+			name:    "response envelope",
+			body:    `{"messageId":"m1","response":{"temperature":{"targetTemperature":21}}}`,
+			wantKey: "temperature",
+		},
+		{
+			name:       "result envelope",
+			body:       `{"messageId":"m1","result":{"temperature":{"targetTemperature":21}}}`,
+			wantKey:    "temperature",
+			mismatches: 1,
+		},
+		{
+			name:       "bare object",
+			body:       `{"temperature":{"targetTemperature":21}}`,
+			wantKey:    "temperature",
+			mismatches: 1,
+		},
+		{
+			name:       "not JSON",
+			body:       `<html>maintenance</html>`,
+			wantErr:    true,
+			mismatches: 1,
+		},
+		{
+			name:       "envelope with non-object payload",
+			body:       `{"response":"gone"}`,
+			wantErr:    true,
+			mismatches: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &Client{}
+			payload, err := client.decodeObjectEnvelope("/devices/x/state", []byte(tt.body))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("decodeObjectEnvelope() = %v, want error", payload)
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("decodeObjectEnvelope() error: %v", err)
+				}
+				if _, ok := payload[tt.wantKey]; !ok {
+					t.Errorf("payload %v is missing key %q", payload, tt.wantKey)
+				}
+			}
+			if got := client.SchemaMismatches(); got != tt.mismatches {
+				t.Errorf("SchemaMismatches() = %d, want %d", got, tt.mismatches)
+			}
+		})
+	}
+}
+
+func TestDecodeDeviceListVariants(t *testing.T) {
+	device := `{"deviceId":"d1","deviceInfo":{"deviceType":"DEVICE_AIR_CONDITIONER","alias":"Sala"}}`
+	tests := []struct {
+		name       string
+		body       string
+		wantCount  int
+		wantErr    bool
+		mismatches int64
+	}{
+		{
+			name:      "response envelope",
+			body:      `{"messageId":"m1","response":[` + device + `]}`,
+			wantCount: 1,
+		},
+		{
+			name:       "result envelope",
+			body:       `{"messageId":"m1","result":[` + device + `]}`,
+			wantCount:  1,
+			mismatches: 1,
+		},
+		{
+			name:       "bare array",
+			body:       `[` + device + `]`,
+			wantCount:  1,
+			mismatches: 1,
+		},
+		{
+			name:       "no known variant",
+			body:       `{"response":{"not":"a list"}}`,
+			wantErr:    true,
+			mismatches: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &Client{}
+			list, err := client.decodeDeviceList([]byte(tt.body))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("decodeDeviceList() = %v, want error", list)
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("decodeDeviceList() error: %v", err)
+				}
+				if len(list) != tt.wantCount {
+					t.Fatalf("got %d device(s), want %d", len(list), tt.wantCount)
+				}
+				if list[0].DeviceID != "d1" {
+					t.Errorf("DeviceID = %q, want d1", list[0].DeviceID)
+				}
+			}
+			if got := client.SchemaMismatches(); got != tt.mismatches {
+				t.Errorf("SchemaMismatches() = %d, want %d", got, tt.mismatches)
+			}
+		})
+	}
+}
+
+func TestRedactPayloadMasksSensitiveValues(t *testing.T) {
+	body := `{
+		"accessToken": "secret-token",
+		"deviceId": "abc123",
+		"response": {"certificatePem": "-----BEGIN CERTIFICATE-----", "privateKey": "pk"},
+		"temperature": {"targetTemperature": 21}
+	}`
+
+	redacted := redactPayload([]byte(body))
+
+	for _, secret := range []string{"secret-token", "abc123", "BEGIN CERTIFICATE", `"pk"`} {
+		if strings.Contains(redacted, secret) {
+			t.Errorf("redacted payload still contains %q: %s", secret, redacted)
+		}
+	}
+	// The structure survives so a schema change stays recognizable
+	for _, kept := range []string{"accessToken", "targetTemperature", "21"} {
+		if !strings.Contains(redacted, kept) {
+			t.Errorf("redacted payload lost %q: %s", kept, redacted)
+		}
+	}
+}
+
+func TestRedactPayloadHandlesNonJSON(t *testing.T) {
+	redacted := redactPayload([]byte("<html>secret</html>"))
+	if strings.Contains(redacted, "secret") {
+		t.Errorf("non-JSON payload leaked into the log: %s", redacted)
+	}
+}
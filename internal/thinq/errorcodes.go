@@ -0,0 +1,38 @@
+package thinq
+
+// errorDescriptions maps known device error/notification codes to
+// human-readable descriptions. Codes observed in the wild vary by model;
+// unknown codes are reported as-is.
+var errorDescriptions = map[string]string{
+	"ERROR_NO":               "No error",
+	"FILTER_ALARM":           "Filter needs cleaning",
+	"FILTER_CLEAN_ALARM":     "Filter needs cleaning",
+	"CH01":                   "Indoor unit temperature sensor fault",
+	"CH02":                   "Indoor unit pipe sensor fault",
+	"CH05":                   "Communication fault between indoor and outdoor units",
+	"CH06":                   "Outdoor unit pipe sensor fault",
+	"CH10":                   "Indoor fan motor fault",
+	"CH21":                   "Compressor overcurrent (IPM fault)",
+	"CH22":                   "Compressor input overcurrent",
+	"CH23":                   "DC link voltage fault",
+	"CH26":                   "Compressor startup failure",
+	"CH44":                   "Outdoor air temperature sensor fault",
+	"CH45":                   "Outdoor condenser sensor fault",
+	"CH60":                   "Outdoor unit EEPROM fault",
+	"CH61":                   "Condenser overheating",
+	"CH62":                   "Inverter heatsink overheating",
+	"CH67":                   "Outdoor fan motor lock",
+	"WATER_DRAIN_PUMP_ALARM": "Condensate drain pump fault",
+}
+
+// DescribeError returns a human-readable description for a device error
+// code, and whether the code was recognized.
+func DescribeError(code string) (string, bool) {
+	description, ok := errorDescriptions[code]
+	return description, ok
+}
+
+// IsErrorClear reports whether the code means "no error".
+func IsErrorClear(code string) bool {
+	return code == "" || code == "ERROR_NO"
+}
@@ -0,0 +1,116 @@
+package thinq
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Event represents a parsed DEVICE_STATUS event received over MQTT.
+// Pointer fields are nil when the corresponding resource was not present
+// in the report (events only carry the fields that changed).
+type Event struct {
+	DeviceID           string
+	PushType           string
+	TargetTemperature  *float64
+	CurrentTemperature *float64
+	CoolTarget         *float64 // twoSetTemperature cool bound (AUTO mode)
+	HeatTarget         *float64 // twoSetTemperature heat bound (AUTO mode)
+	Humidity           *float64 // relative humidity in percent
+	TargetHumidity     *float64 // dehumidifier target humidity in percent
+	JobMode            string
+	PowerOn            *bool
+	ErrorCode          string
+}
+
+// ParseEvent decodes a raw MQTT payload into an Event.
+// It returns an error when the payload is not valid JSON or has no deviceId.
+func ParseEvent(payload []byte) (*Event, error) {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(payload, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse event payload: %w", err)
+	}
+
+	deviceID, ok := raw["deviceId"].(string)
+	if !ok {
+		return nil, fmt.Errorf("event has no deviceId")
+	}
+
+	event := &Event{DeviceID: deviceID}
+	event.PushType, _ = raw["pushType"].(string)
+
+	report, ok := raw["report"].(map[string]interface{})
+	if !ok {
+		return event, nil
+	}
+
+	parseReport(event, report)
+	return event, nil
+}
+
+// EventFromState converts a polled device state document (the report shape
+// without the push envelope) into an Event.
+func EventFromState(deviceID string, state map[string]interface{}) *Event {
+	event := &Event{DeviceID: deviceID, PushType: "DEVICE_STATUS"}
+	parseReport(event, state)
+	return event
+}
+
+// parseReport fills the event from the resources present in a report.
+func parseReport(event *Event, report map[string]interface{}) {
+	if temperature, ok := report["temperature"].(map[string]interface{}); ok {
+		if target, ok := temperature["targetTemperature"].(float64); ok {
+			event.TargetTemperature = &target
+		}
+		if current, ok := temperature["currentTemperature"].(float64); ok {
+			event.CurrentTemperature = &current
+		}
+	}
+
+	if twoSet, ok := report["twoSetTemperature"].(map[string]interface{}); ok {
+		if cool, ok := twoSet["coolTargetTemperature"].(float64); ok {
+			event.CoolTarget = &cool
+		}
+		if heat, ok := twoSet["heatTargetTemperature"].(float64); ok {
+			event.HeatTarget = &heat
+		}
+	}
+
+	if humidity, ok := report["humidity"].(map[string]interface{}); ok {
+		if current, ok := humidity["currentHumidity"].(float64); ok {
+			event.Humidity = &current
+		}
+		if target, ok := humidity["targetHumidity"].(float64); ok {
+			event.TargetHumidity = &target
+		}
+	}
+
+	// Each device type reports its job mode under its own resource name
+	for _, key := range []string{"airConJobMode", "dehumidifierJobMode", "airPurifierJobMode"} {
+		if jobMode, ok := report[key].(map[string]interface{}); ok {
+			event.JobMode, _ = jobMode["currentJobMode"].(string)
+			break
+		}
+	}
+
+	if operation, ok := report["operation"].(map[string]interface{}); ok {
+		for _, key := range []string{"airConOperationMode", "dehumidifierOperationMode", "airPurifierOperationMode"} {
+			if mode, ok := operation[key].(string); ok {
+				powerOn := mode == "POWER_ON"
+				event.PowerOn = &powerOn
+				break
+			}
+		}
+	}
+
+	// The error resource appears either as a plain code or nested
+	switch errValue := report["error"].(type) {
+	case string:
+		event.ErrorCode = errValue
+	case map[string]interface{}:
+		if code, ok := errValue["errorCode"].(string); ok {
+			event.ErrorCode = code
+		} else if code, ok := errValue["code"].(string); ok {
+			event.ErrorCode = code
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package thinq
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+)
+
+// HTTPOptions tunes the REST client's HTTP behavior for restrictive
+// networks. Zero values keep sensible defaults; the standard HTTP_PROXY /
+// HTTPS_PROXY / NO_PROXY environment variables are always honored unless
+// an explicit ProxyURL overrides them.
+type HTTPOptions struct {
+	Timeout               time.Duration // overall request timeout (default 30s)
+	DialTimeout           time.Duration // TCP connect timeout (default 10s)
+	TLSHandshakeTimeout   time.Duration // TLS handshake timeout (default 10s)
+	ResponseHeaderTimeout time.Duration // wait for response headers (0 = none)
+	ProxyURL              string        // explicit proxy, e.g. http://proxy.corp:3128
+	MaxIdleConnsPerHost   int           // pooled connections per host (0 = Go default)
+	DisableKeepAlives     bool          // open a fresh connection per request
+}
+
+// ConfigureHTTP rebuilds the client's transport from the given options.
+// The OpenTelemetry instrumentation is preserved.
+func (c *Client) ConfigureHTTP(opts HTTPOptions) error {
+	dialTimeout := opts.DialTimeout
+	if dialTimeout == 0 {
+		dialTimeout = 10 * time.Second
+	}
+	tlsTimeout := opts.TLSHandshakeTimeout
+	if tlsTimeout == 0 {
+		tlsTimeout = 10 * time.Second
+	}
+
+	transport := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   dialTimeout,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		ForceAttemptHTTP2:     true,
+		TLSHandshakeTimeout:   tlsTimeout,
+		ResponseHeaderTimeout: opts.ResponseHeaderTimeout,
+		MaxIdleConnsPerHost:   opts.MaxIdleConnsPerHost,
+		DisableKeepAlives:     opts.DisableKeepAlives,
+		IdleConnTimeout:       90 * time.Second,
+	}
+	if opts.ProxyURL != "" {
+		proxyURL, err := url.Parse(opts.ProxyURL)
+		if err != nil {
+			return fmt.Errorf("invalid proxy URL %q: %w", opts.ProxyURL, err)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	if opts.Timeout > 0 {
+		c.httpClient.Timeout = opts.Timeout
+	}
+	c.httpClient.Transport = otelhttp.NewTransport(transport)
+	return nil
+}
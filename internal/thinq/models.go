@@ -1,5 +1,12 @@
 package thinq
 
+// Device types the daemon knows how to control.
+const (
+	DeviceTypeAirConditioner = "DEVICE_AIR_CONDITIONER"
+	DeviceTypeDehumidifier   = "DEVICE_DEHUMIDIFIER"
+	DeviceTypeAirPurifier    = "DEVICE_AIR_PURIFIER"
+)
+
 // DeviceListResponse represents the response from the device list API
 type DeviceListResponse struct {
 	MessageID string           `json:"messageId"`
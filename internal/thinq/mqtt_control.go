@@ -0,0 +1,45 @@
+package thinq
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ControlPublisher publishes a raw payload to an MQTT topic. The daemon
+// provides one backed by its live broker connection.
+type ControlPublisher func(topic string, payload []byte) error
+
+// mqttControl routes device control commands over the MQTT connection
+// instead of the REST control endpoint.
+type mqttControl struct {
+	publish ControlPublisher
+	topic   string
+}
+
+// EnableMQTTControl routes subsequent control commands through the given
+// publisher on the first publication topic granted with the certificate.
+// It reports false (and changes nothing) when the platform granted no
+// publication topic to this client.
+func (c *Client) EnableMQTTControl(publish ControlPublisher, publications []string) bool {
+	if len(publications) == 0 {
+		return false
+	}
+	c.mqttControl = &mqttControl{publish: publish, topic: publications[0]}
+	return true
+}
+
+// send publishes one control command on the client's publication topic,
+// mirroring the body of the REST control endpoint.
+func (m *mqttControl) send(deviceID string, payload map[string]interface{}) error {
+	message, err := json.Marshal(map[string]interface{}{
+		"deviceId": deviceID,
+		"control":  payload,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal control message: %w", err)
+	}
+	if err := m.publish(m.topic, message); err != nil {
+		return fmt.Errorf("failed to publish control message: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package thinq
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// GetDeviceProfile fetches the capability profile of a device: which
+// properties the model exposes and the values each one accepts.
+func (c *Client) GetDeviceProfile(deviceID string) (map[string]interface{}, error) {
+	url := fmt.Sprintf("%s/devices/%s/profile", c.baseURL, deviceID)
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	c.setHeaders(req)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	return c.decodeObjectEnvelope(fmt.Sprintf("/devices/%s/profile", deviceID), body)
+}
+
+// SupportsPowerSave reports whether a device profile exposes the native
+// powerSave switch. The profile's property section is a map on most
+// models but an array of maps on some.
+func SupportsPowerSave(profile map[string]interface{}) bool {
+	switch property := profile["property"].(type) {
+	case map[string]interface{}:
+		_, ok := property["powerSave"]
+		return ok
+	case []interface{}:
+		for _, item := range property {
+			if m, ok := item.(map[string]interface{}); ok {
+				if _, ok := m["powerSave"]; ok {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
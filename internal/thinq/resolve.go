@@ -0,0 +1,51 @@
+package thinq
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ResolveDevice resolves a human-friendly name to a device ID. It accepts
+// the device ID itself, the exact alias (case-insensitive) or a unique
+// alias prefix or substring, so "sala" finds "Ar da Sala" without anyone
+// remembering LG's UUID-like IDs. On ambiguity the error lists the
+// matching aliases.
+func ResolveDevice(devices []Device, name string) (string, error) {
+	// Exact device ID or alias wins
+	for _, device := range devices {
+		if device.DeviceID == name || strings.EqualFold(device.Alias, name) {
+			return device.DeviceID, nil
+		}
+	}
+
+	lower := strings.ToLower(name)
+	match := func(test func(alias string) bool) []Device {
+		var matched []Device
+		for _, device := range devices {
+			if test(strings.ToLower(device.Alias)) {
+				matched = append(matched, device)
+			}
+		}
+		return matched
+	}
+
+	matched := match(func(alias string) bool { return strings.HasPrefix(alias, lower) })
+	if len(matched) == 0 {
+		matched = match(func(alias string) bool { return strings.Contains(alias, lower) })
+	}
+
+	switch len(matched) {
+	case 0:
+		return "", fmt.Errorf("unknown device %q", name)
+	case 1:
+		return matched[0].DeviceID, nil
+	default:
+		aliases := make([]string, len(matched))
+		for i, device := range matched {
+			aliases[i] = device.Alias
+		}
+		sort.Strings(aliases)
+		return "", fmt.Errorf("ambiguous device %q: matches %s", name, strings.Join(aliases, ", "))
+	}
+}
@@ -0,0 +1,18 @@
+//go:build !windows
+
+// Package winsvc runs the daemon under the Windows service control
+// manager, translating SCM stop requests into a graceful shutdown. Off
+// Windows every function is a trivial passthrough.
+package winsvc
+
+// IsService always reports false off Windows.
+func IsService() bool {
+	return false
+}
+
+// Run executes body directly with a stop channel that never closes;
+// signal handling is the caller's responsibility off Windows.
+func Run(name string, body func(stop <-chan struct{})) error {
+	body(make(chan struct{}))
+	return nil
+}
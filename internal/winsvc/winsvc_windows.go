@@ -0,0 +1,57 @@
+//go:build windows
+
+// Package winsvc runs the daemon under the Windows service control
+// manager, translating SCM stop requests into a graceful shutdown. Off
+// Windows every function is a trivial passthrough.
+package winsvc
+
+import (
+	"golang.org/x/sys/windows/svc"
+)
+
+// IsService reports whether the process was started by the service
+// control manager rather than interactively.
+func IsService() bool {
+	isService, err := svc.IsWindowsService()
+	return err == nil && isService
+}
+
+// Run executes body under service control. The channel passed to body is
+// closed when the service manager requests a stop or shutdown.
+func Run(name string, body func(stop <-chan struct{})) error {
+	return svc.Run(name, &handler{body: body})
+}
+
+type handler struct {
+	body func(stop <-chan struct{})
+}
+
+func (h *handler) Execute(args []string, requests <-chan svc.ChangeRequest, status chan<- svc.Status) (bool, uint32) {
+	const accepted = svc.AcceptStop | svc.AcceptShutdown
+	status <- svc.Status{State: svc.StartPending}
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		h.body(stop)
+		close(done)
+	}()
+
+	status <- svc.Status{State: svc.Running, Accepts: accepted}
+	for {
+		select {
+		case request := <-requests:
+			switch request.Cmd {
+			case svc.Interrogate:
+				status <- request.CurrentStatus
+			case svc.Stop, svc.Shutdown:
+				status <- svc.Status{State: svc.StopPending}
+				close(stop)
+				<-done
+				return false, 0
+			}
+		case <-done:
+			return false, 0
+		}
+	}
+}